@@ -76,6 +76,13 @@ func (b *addChannelToChannelGroupBuilder) QueryParam(queryParam map[string]strin
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *addChannelToChannelGroupBuilder) RetryPolicy(policy RetryPolicy) *addChannelToChannelGroupBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs AddChannelToChannelGroup request
 func (b *addChannelToChannelGroupBuilder) Execute() (
 	*AddChannelToChannelGroupResponse, StatusResponse, error) {
@@ -92,6 +99,7 @@ type addChannelOpts struct {
 	Channels     []string
 	ChannelGroup string
 	QueryParam   map[string]string
+	RetryPolicy  *RetryPolicy
 	Transport    http.RoundTripper
 	ctx          Context
 }
@@ -185,6 +193,17 @@ func (o *addChannelOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *addChannelOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *addChannelOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // AddChannelToChannelGroupResponse is the struct returned when the Execute function of AddChannelToChannelGroup is called.
 type AddChannelToChannelGroupResponse struct {
 }
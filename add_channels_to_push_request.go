@@ -82,6 +82,13 @@ func (b *addPushNotificationsOnChannelsBuilder) QueryParam(queryParam map[string
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *addPushNotificationsOnChannelsBuilder) RetryPolicy(policy RetryPolicy) *addPushNotificationsOnChannelsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs add Push Notifications on channels request
 func (b *addPushNotificationsOnChannelsBuilder) Execute() (*AddPushNotificationsOnChannelsResponse, StatusResponse, error) {
 	_, status, err := executeRequest(b.opts)
@@ -98,6 +105,7 @@ type addChannelsToPushOpts struct {
 	PushType        PNPushType
 	DeviceIDForPush string
 	QueryParam      map[string]string
+	RetryPolicy     *RetryPolicy
 	Transport       http.RoundTripper
 	ctx             Context
 	Topic           string
@@ -212,3 +220,14 @@ func (o *addChannelsToPushOpts) telemetryManager() *TelemetryManager {
 func (o *addChannelsToPushOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *addChannelsToPushOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *addChannelsToPushOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
@@ -1,40 +1,139 @@
 package pubnub
 
 import (
-	"golang.org/x/net/http2"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
 )
 
+// DialContextFunc dials the connections of the clients, see Config.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
 // NewHTTP1Client creates a new HTTP 1 client with a new transport initialized with connect and read timeout
 func NewHTTP1Client(connectTimeout, responseReadTimeout, maxIdleConnsPerHost int) *http.Client {
-	transport := &http.Transport{
-		Dial: (&net.Dialer{
-			// Covers establishing a new TCP connection
-			Timeout: time.Duration(connectTimeout) * time.Second,
-		}).Dial,
+	return NewHTTPClient(&Config{
+		ConnectTimeout:      connectTimeout,
 		MaxIdleConnsPerHost: maxIdleConnsPerHost,
-	}
+	}, responseReadTimeout)
+}
 
-	client := &http.Client{
-		Transport: transport,
+// NewHTTP2Client creates a new HTTP 2 client with a new transport initialized with connect and read timeout
+func NewHTTP2Client(connectTimeout int, responseReadTimeout int) *http.Client {
+	return NewHTTPClient(&Config{
+		ConnectTimeout: connectTimeout,
+		UseHTTP2:       true,
+	}, responseReadTimeout)
+}
+
+// NewHTTPClient creates a new client with the transport options of config:
+// the connect timeout, the proxy, the dialer, the keep-alive settings and
+// HTTP 2. It's used for the subscribe, non-subscribe and file-upload requests.
+func NewHTTPClient(config *Config, responseReadTimeout int) *http.Client {
+	return &http.Client{
+		Transport: newTransport(config),
 		// Covers the entire exchange from Dial to reading the body
 		Timeout: time.Duration(responseReadTimeout) * time.Second,
 	}
+}
+
+func newTransport(config *Config) *http.Transport {
+	connectTimeout := time.Duration(config.ConnectTimeout) * time.Second
+	dialer := &net.Dialer{
+		// Covers establishing a new TCP connection
+		Timeout:   connectTimeout,
+		KeepAlive: time.Duration(config.KeepAlive) * time.Second,
+	}
+
+	dial := dialer.DialContext
+	if config.DialContext != nil {
+		custom := config.DialContext
+		dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if connectTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, connectTimeout)
+				defer cancel()
+			}
+			return custom(ctx, network, addr)
+		}
+	}
+
+	transport := &http.Transport{
+		DialContext:         dial,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     time.Duration(config.IdleConnTimeout) * time.Second,
+		ForceAttemptHTTP2:   config.UseHTTP2,
+	}
+	if config.ProxyURL != "" {
+		transport.Proxy = proxyFunc(config.ProxyURL, config.ProxyUsername, config.ProxyPassword)
+	}
+	if config.TLSRootCAs != nil || len(config.TLSCertificates) > 0 ||
+		config.TLSMinVersion != 0 || len(config.TLSPinnedKeys) > 0 {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:      config.TLSRootCAs,
+			Certificates: config.TLSCertificates,
+			MinVersion:   config.TLSMinVersion,
+		}
+		if len(config.TLSPinnedKeys) > 0 {
+			transport.TLSClientConfig.VerifyConnection = verifyPins(config.TLSPinnedKeys)
+		}
+	}
 
-	return client
+	return transport
 }
 
-// NewHTTP2Client creates a new HTTP 2 client with a new transport initialized with connect and read timeout
-func NewHTTP2Client(connectTimeout int, responseReadTimeout int) *http.Client {
-	transport := &http2.Transport{}
+// proxyFunc returns the http.Transport.Proxy of a proxy URL, the credentials
+// override the user info of the URL. An invalid URL fails the requests
+// rather than bypassing the proxy.
+func proxyFunc(rawURL, username, password string) func(*http.Request) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err == nil && username != "" {
+		proxyURL.User = url.UserPassword(username, password)
+	}
 
-	client := &http.Client{
-		Transport: transport,
-		// Covers the entire exchange from Dial to reading the body
-		Timeout: time.Duration(responseReadTimeout) * time.Second,
+	return func(*http.Request) (*url.URL, error) {
+		if err != nil {
+			return nil, err
+		}
+		return proxyURL, nil
 	}
+}
+
+// SPKIPin returns the pin of the public key of cert, the base64 encoded
+// SHA-256 of its SubjectPublicKeyInfo, for Config.TLSPinnedKeys.
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
 
-	return client
+// verifyPins checks that a certificate of the chain presented by the server
+// matches one of pins, after the usual verification of the chain.
+func verifyPins(pins []string) func(tls.ConnectionState) error {
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pinned[pin] = true
+	}
+
+	return func(state tls.ConnectionState) error {
+		certs := state.PeerCertificates
+		for _, chain := range state.VerifiedChains {
+			certs = append(certs, chain...)
+		}
+		presented := make([]string, 0, len(certs))
+		for _, cert := range certs {
+			pin := SPKIPin(cert)
+			if pinned[pin] {
+				return nil
+			}
+			presented = append(presented, pin)
+		}
+		return pnerr.NewPinningError(state.ServerName, presented)
+	}
 }
@@ -0,0 +1,182 @@
+package pubnub
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTP2ClientTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	client := NewHTTP2Client(7, 20)
+	transport := client.Transport.(*http.Transport)
+	assert.True(transport.ForceAttemptHTTP2)
+	assert.Nil(transport.Proxy)
+	assert.Equal(20*time.Second, client.Timeout)
+
+	client = NewHTTP1Client(7, 20, 5)
+	transport = client.Transport.(*http.Transport)
+	assert.False(transport.ForceAttemptHTTP2)
+	assert.Equal(5, transport.MaxIdleConnsPerHost)
+}
+
+func TestClientProxy(t *testing.T) {
+	assert := assert.New(t)
+	authorization := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a proxy gets the absolute URL of the origin
+		if r.URL.Host != "ps.pndsn.com" || r.URL.Path != "/time/0" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		authorization <- r.Header.Get("Proxy-Authorization")
+		w.Write([]byte(`[15000000000000000]`))
+	}))
+	defer proxy.Close()
+
+	config := NewDemoConfig()
+	config.Secure = false
+	config.ProxyURL = proxy.URL
+	config.ProxyUsername = "user"
+	config.ProxyPassword = "p@ss"
+	pn := NewPubNub(config)
+
+	res, _, err := pn.Time().Execute()
+	assert.Nil(err)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timetoken)
+	}
+	assert.Equal("Basic "+base64.StdEncoding.EncodeToString([]byte("user:p@ss")), <-authorization)
+}
+
+func TestClientInvalidProxy(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ProxyURL = "://invalid"
+	pn := NewPubNub(config)
+
+	_, _, err := pn.Time().Execute()
+	assert.NotNil(err)
+}
+
+func TestClientDialContextUnixSocket(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "dial")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "sidecar.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skip("unix sockets not supported:", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[15000000000000000]`))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	config := NewDemoConfig()
+	config.Secure = false
+	config.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socket)
+	}
+	pn := NewPubNub(config)
+
+	res, _, err := pn.Time().Execute()
+	assert.Nil(err)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timetoken)
+	}
+
+	// the subscribe client uses the same dialer
+	transport := pn.GetSubscribeClient().Transport.(*http.Transport)
+	conn, err := transport.DialContext(context.Background(), "tcp", "ps.pndsn.com:80")
+	assert.Nil(err)
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func newTLSTimeServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[15000000000000000]`))
+	}))
+}
+
+func newTLSConfig(server *httptest.Server) *Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	config := NewDemoConfig()
+	config.Origin = strings.TrimPrefix(server.URL, "https://")
+	config.TLSRootCAs = roots
+	config.TLSMinVersion = tls.VersionTLS12
+	return config
+}
+
+func TestClientTLSRootCAs(t *testing.T) {
+	assert := assert.New(t)
+	server := newTLSTimeServer()
+	defer server.Close()
+
+	pn := NewPubNub(newTLSConfig(server))
+	res, _, err := pn.Time().Execute()
+	assert.Nil(err)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timetoken)
+	}
+
+	// the server certificate isn't trusted by default
+	config := newTLSConfig(server)
+	config.TLSRootCAs = nil
+	pn = NewPubNub(config)
+	_, status, err := pn.Time().Execute()
+	assert.NotNil(err)
+	assert.Equal(PNTLSFailureCategory, status.Category)
+}
+
+func TestClientTLSPinnedKeys(t *testing.T) {
+	assert := assert.New(t)
+	server := newTLSTimeServer()
+	defer server.Close()
+
+	config := newTLSConfig(server)
+	config.TLSPinnedKeys = []string{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", SPKIPin(server.Certificate())}
+	pn := NewPubNub(config)
+	res, _, err := pn.Time().Execute()
+	assert.Nil(err)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timetoken)
+	}
+
+	config = newTLSConfig(server)
+	config.TLSPinnedKeys = []string{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}
+	pn = NewPubNub(config)
+	_, status, err := pn.Time().Execute()
+	assert.NotNil(err)
+	assert.Equal(PNTLSPinningFailureCategory, status.Category)
+	assert.False(pnerr.IsRetryable(err))
+
+	var pinErr *pnerr.PinningError
+	if assert.True(errors.As(err, &pinErr)) {
+		assert.Contains(pinErr.Pins, SPKIPin(server.Certificate()))
+	}
+}
@@ -1,13 +1,18 @@
 package pubnub
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
 	"sync"
+
+	"github.com/pubnub/go/v7/crypto"
 )
 
 const (
-	presenceTimeout = 0
+	presenceTimeout          = 0
+	defaultListenerQueueSize = 1000
 )
 
 // Config instance is storage for user-provided information which describe further
@@ -15,35 +20,63 @@ const (
 // properties which allow to perform precise PubNub client configuration.
 type Config struct {
 	sync.RWMutex
-	PublishKey                    string             // PublishKey you can get it from admin panel (only required if publishing).
-	SubscribeKey                  string             // SubscribeKey you can get it from admin panel.
-	SecretKey                     string             // SecretKey (only required for modifying/revealing access permissions).
-	AuthKey                       string             // AuthKey If Access Manager is utilized, client will use this AuthKey in all restricted requests.
-	Origin                        string             // Custom Origin if needed
-	UUID                          string             // UUID to be used as a device identifier.
-	CipherKey                     string             // If CipherKey is passed, all communications to/from PubNub will be encrypted.
-	Secure                        bool               // True to use TLS
-	ConnectTimeout                int                // net.Dialer.Timeout
-	NonSubscribeRequestTimeout    int                // http.Client.Timeout for non-subscribe requests
-	SubscribeRequestTimeout       int                // http.Client.Timeout for subscribe requests only
-	FileUploadRequestTimeout      int                // http.Client.Timeout File Upload Request only
-	HeartbeatInterval             int                // The frequency of the pings to the server to state that the client is active
-	PresenceTimeout               int                // The time after which the server will send a timeout for the client
-	MaximumReconnectionRetries    int                // The config sets how many times to retry to reconnect before giving up.
-	MaximumLatencyDataAge         int                // Max time to store the latency data for telemetry
-	FilterExpression              string             // Feature to subscribe with a custom filter expression.
-	PNReconnectionPolicy          ReconnectionPolicy // Reconnection policy selection
-	Log                           *log.Logger        // Logger instance
-	SuppressLeaveEvents           bool               // When true the SDK doesn't send out the leave requests.
-	DisablePNOtherProcessing      bool               // PNOther processing looks for pn_other in the JSON on the recevied message
-	UseHTTP2                      bool               // HTTP2 Flag
-	MessageQueueOverflowCount     int                // When the limit is exceeded by the number of messages received in a single subscribe request, a status event PNRequestMessageCountExceededCategory is fired.
-	MaxIdleConnsPerHost           int                // Used to set the value of HTTP Transport's MaxIdleConnsPerHost.
-	MaxWorkers                    int                // Number of max workers for Publish and Grant requests
-	UsePAMV3                      bool               // Use PAM version 2, Objects requets would still use PAM v3
-	StoreTokensOnGrant            bool               // Will store grant v3 tokens in token manager for further use.
-	FileMessagePublishRetryLimit  int                // The number of tries made in case of Publish File Message failure.
-	UseRandomInitializationVector bool               // When true the IV will be random for all requests and not just file upload. When false the IV will be hardcoded for all requests except File Upload
+	PublishKey                    string                 // PublishKey you can get it from admin panel (only required if publishing).
+	SubscribeKey                  string                 // SubscribeKey you can get it from admin panel.
+	SecretKey                     string                 // SecretKey (only required for modifying/revealing access permissions).
+	AuthKey                       string                 // AuthKey If Access Manager is utilized, client will use this AuthKey in all restricted requests.
+	Origin                        string                 // Custom Origin if needed
+	Origins                       []string               // Ordered list of origins, the requests fail over to the next one after retryable connection failures, the subscribe loop only with a PNReconnectionPolicy. Origin is used when empty.
+	OriginHealthPolicy            OriginHealthPolicy     // When to fail over between Origins.
+	UUID                          string                 // UUID to be used as a device identifier.
+	CipherKey                     string                 // If CipherKey is passed, all communications to/from PubNub will be encrypted.
+	CryptoModule                  crypto.CryptoModule    // Encrypts the messages and files, overrides CipherKey. crypto.NewAesGcmCryptoModule encrypts with AES-GCM.
+	Secure                        bool                   // True to use TLS
+	ConnectTimeout                int                    // net.Dialer.Timeout
+	NonSubscribeRequestTimeout    int                    // http.Client.Timeout for non-subscribe requests
+	SubscribeRequestTimeout       int                    // http.Client.Timeout for subscribe requests only
+	FileUploadRequestTimeout      int                    // http.Client.Timeout File Upload Request only
+	HeartbeatInterval             int                    // The frequency of the pings to the server to state that the client is active
+	PresenceTimeout               int                    // The time after which the server will send a timeout for the client
+	MaximumReconnectionRetries    int                    // The config sets how many times to retry to reconnect before giving up.
+	MaximumLatencyDataAge         int                    // Max time to store the latency data for telemetry
+	FilterExpression              string                 // Feature to subscribe with a custom filter expression.
+	PNReconnectionPolicy          ReconnectionPolicy     // Reconnection policy selection
+	Log                           *log.Logger            // Logger instance
+	SuppressLeaveEvents           bool                   // When true the SDK doesn't send out the leave requests.
+	DisablePNOtherProcessing      bool                   // PNOther processing looks for pn_other in the JSON on the recevied message
+	UseHTTP2                      bool                   // HTTP2 Flag
+	MessageQueueOverflowCount     int                    // When the limit is exceeded by the number of messages received in a single subscribe request, a status event PNRequestMessageCountExceededCategory is fired.
+	MaxIdleConnsPerHost           int                    // Used to set the value of HTTP Transport's MaxIdleConnsPerHost.
+	IdleConnTimeout               int                    // Seconds an idle connection is kept open, 0 means no limit.
+	KeepAlive                     int                    // Seconds between the TCP keep-alive probes, 0 uses the default of net.Dialer and a negative value disables them.
+	ProxyURL                      string                 // URL of the HTTP or HTTPS proxy, e.g. http://proxy.example.com:3128. HTTPS requests go through CONNECT.
+	ProxyUsername                 string                 // Username of the proxy, overrides the user info of ProxyURL.
+	ProxyPassword                 string                 // Password of the proxy.
+	DialContext                   DialContextFunc        // Custom dialer, e.g. to connect through a Unix socket. ConnectTimeout still applies.
+	TLSRootCAs                    *x509.CertPool         // Root CAs verifying the certificates of the origin, the system ones when nil.
+	TLSCertificates               []tls.Certificate      // Client certificates presented to the origin.
+	TLSMinVersion                 uint16                 // Minimum TLS version, e.g. tls.VersionTLS12. 0 uses the default of crypto/tls.
+	TLSPinnedKeys                 []string               // SPKI pins, see SPKIPin. When set, the requests fail with a *pnerr.PinningError unless a certificate of the origin matches one.
+	MaxWorkers                    int                    // Number of max workers for Publish and Grant requests
+	UsePAMV3                      bool                   // Use PAM version 2, Objects requets would still use PAM v3
+	StoreTokensOnGrant            bool                   // Will store grant v3 tokens in token manager for further use.
+	FileMessagePublishRetryLimit  int                    // The number of tries made in case of Publish File Message failure.
+	UseRandomInitializationVector bool                   // When true the IV will be random for all requests and not just file upload. When false the IV will be hardcoded for all requests except File Upload
+	ListenerQueueSize             int                    // Capacity of the delivery queue of each listener.
+	ListenerOverflowPolicy        ListenerOverflowPolicy // What happens when the delivery queue of a listener is full. The default PNListenerOverflowBlock delivers every event, the other policies drop events and are opt-in.
+	CatchUpOnReconnect            bool                   // When true the messages published during a network outage are fetched from history and announced before subscribe resumes.
+	DedupeCacheSize               int                    // Number of recent messages remembered to suppress the duplicates received by subscribe, 0 disables de-duplication.
+	DedupeCacheTTL                int                    // Seconds a message is remembered by the de-duplication cache.
+	CursorStore                   CursorStore            // When set the subscribe cursor is saved after every subscribe response and the first Subscribe resumes from the stored one.
+	DetectSequenceGaps            bool                   // When true a PNSequenceGapCategory status is announced when the sequence numbers of a publisher skip values. Messages published to channels not subscribed to count as gaps too.
+	PublishRateLimit              RateLimit              // Rate of the Publish, Signal and Fire requests of this instance, unlimited when Rate is 0.
+	ChannelPublishRateLimit       RateLimit              // Rate of the Publish, Signal and Fire requests to each channel, unlimited when Rate is 0.
+	ChannelPublishRateLimits      map[string]RateLimit   // Rates of the publishes to specific channels, overriding ChannelPublishRateLimit.
+	PublishRateLimitMode          RateLimitMode          // What happens to the publishes exceeding the rate limits.
+	PublishPipelineSize           int                    // Publishes pending per channel in the PublishPipeline, ExecuteAsync blocks once reached.
+	RetryPolicy                   RetryPolicy            // Retries of the non-subscribe requests after transient failures, disabled by default. Overridden by the RetryPolicy method of the builders.
+	Serializer                    Serializer             // Encodes the messages, meta and state sent and decodes the messages received, JSONSerializer when nil.
+	RestorePresenceState          bool                   // When true the state set with Subscribe or SetState is set again when the server may have lost it: after a timeout of this UUID, a reconnection, a failed heartbeat or a change of UUID.
 }
 
 // NewDemoConfig initiates the config with demo keys, for tests only.
@@ -80,6 +113,11 @@ func NewConfig(uuid string) *Config {
 		StoreTokensOnGrant:            true,
 		FileMessagePublishRetryLimit:  5,
 		UseRandomInitializationVector: true,
+		ListenerQueueSize:             defaultListenerQueueSize,
+		ListenerOverflowPolicy:        PNListenerOverflowBlock,
+		DedupeCacheTTL:                600,
+		PublishRateLimitMode:          PNRateLimitContext,
+		PublishPipelineSize:           defaultPublishPipelineSize,
 	}
 
 	return &c
@@ -0,0 +1,235 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	aesGcmID = "AGCM"
+
+	// gcmChunkSize is the size of the plaintext chunks of the streams, each
+	// one is sealed separately.
+	gcmChunkSize = 64 * 1024
+)
+
+// AesGcmCryptor is AES-256-GCM with the key derived from the cipher key by
+// SHA-256. The nonce is random and stored in the header.
+//
+// The streams are sealed in chunks of 64 KiB, the nonce of a chunk is the
+// nonce of the stream XORed with its index and the last chunk is
+// authenticated as such, so a truncated stream fails to decrypt.
+type AesGcmCryptor struct {
+	aead cipher.AEAD
+}
+
+// NewAesGcmCryptor returns an AesGcmCryptor.
+func NewAesGcmCryptor(cipherKey string) (*AesGcmCryptor, error) {
+	if cipherKey == "" {
+		return nil, errors.New("crypto: cipher key is empty")
+	}
+	key := sha256.Sum256([]byte(cipherKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AesGcmCryptor{aead: aead}, nil
+}
+
+// ID returns the id of the AES-GCM cryptor.
+func (c *AesGcmCryptor) ID() string {
+	return aesGcmID
+}
+
+// Encrypt encrypts data.
+func (c *AesGcmCryptor) Encrypt(data []byte) (*EncryptedData, error) {
+	nonce, err := c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedData{
+		Metadata: nonce,
+		Data:     c.aead.Seal(nil, nonce, data, nil),
+	}, nil
+}
+
+// Decrypt decrypts data.
+func (c *AesGcmCryptor) Decrypt(data *EncryptedData) ([]byte, error) {
+	if len(data.Metadata) != c.aead.NonceSize() {
+		return nil, fmt.Errorf("crypto: invalid nonce length %d", len(data.Metadata))
+	}
+	decrypted, err := c.aead.Open(nil, data.Metadata, data.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("crypto: decryption failed: %w", err)
+	}
+	return decrypted, nil
+}
+
+// EncryptStream encrypts the stream as it's read.
+func (c *AesGcmCryptor) EncryptStream(reader io.Reader) (*EncryptedStreamData, error) {
+	nonce, err := c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedStreamData{
+		Metadata: nonce,
+		Reader: &gcmSealReader{
+			gcmChunks: gcmChunks{aead: c.aead, nonce: nonce},
+			src:       reader,
+			plain:     make([]byte, gcmChunkSize),
+		},
+	}, nil
+}
+
+// DecryptStream decrypts the stream encrypted by EncryptStream as it's
+// read.
+func (c *AesGcmCryptor) DecryptStream(data *EncryptedStreamData) (io.Reader, error) {
+	if len(data.Metadata) != c.aead.NonceSize() {
+		return nil, fmt.Errorf("crypto: invalid nonce length %d", len(data.Metadata))
+	}
+	return &gcmOpenReader{
+		gcmChunks: gcmChunks{aead: c.aead, nonce: data.Metadata},
+		src:       bufio.NewReader(data.Reader),
+		sealed:    make([]byte, gcmChunkSize+c.aead.Overhead()),
+	}, nil
+}
+
+// EncryptedStreamSize returns the size of the nonce and of the sealed
+// chunks, a stream always ends with a chunk shorter than gcmChunkSize.
+func (c *AesGcmCryptor) EncryptedStreamSize(size int64) (int, int64) {
+	return c.aead.NonceSize(), size + (size/gcmChunkSize+1)*int64(c.aead.Overhead())
+}
+
+func (c *AesGcmCryptor) nonce() ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// gcmChunks holds the state shared by the sealing and opening of the chunks
+// of a stream.
+type gcmChunks struct {
+	aead  cipher.AEAD
+	nonce []byte
+	index uint64
+	buf   []byte
+	// out is the part of buf not read yet.
+	out  []byte
+	done bool
+	err  error
+}
+
+// chunkNonce returns the nonce of the next chunk.
+func (g *gcmChunks) chunkNonce() []byte {
+	nonce := append([]byte{}, g.nonce...)
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], g.index)
+	for i := range index {
+		nonce[len(nonce)-len(index)+i] ^= index[i]
+	}
+	g.index++
+	return nonce
+}
+
+// read copies the pending output to p, next produces the output of the next
+// chunk.
+func (g *gcmChunks) read(p []byte, next func() error) (int, error) {
+	for len(g.out) == 0 {
+		if g.err != nil {
+			return 0, g.err
+		}
+		if g.done {
+			return 0, io.EOF
+		}
+		if err := next(); err != nil {
+			g.err = err
+		}
+	}
+	n := copy(p, g.out)
+	g.out = g.out[n:]
+	return n, nil
+}
+
+func chunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+type gcmSealReader struct {
+	gcmChunks
+	src   io.Reader
+	plain []byte
+}
+
+func (r *gcmSealReader) Read(p []byte) (int, error) {
+	return r.read(p, r.next)
+}
+
+func (r *gcmSealReader) next() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		// a stream of a multiple of the chunk size ends with an empty chunk
+		last = true
+	default:
+		return err
+	}
+
+	r.buf = r.aead.Seal(r.buf[:0], r.chunkNonce(), r.plain[:n], chunkAdditionalData(last))
+	r.out = r.buf
+	r.done = last
+	return nil
+}
+
+type gcmOpenReader struct {
+	gcmChunks
+	src    *bufio.Reader
+	sealed []byte
+}
+
+func (r *gcmOpenReader) Read(p []byte) (int, error) {
+	return r.read(p, r.next)
+}
+
+func (r *gcmOpenReader) next() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	last := false
+	switch err {
+	case nil:
+		// a full chunk is the last one when nothing follows
+		if _, peekErr := r.src.Peek(1); peekErr == io.EOF {
+			last = true
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return fmt.Errorf("crypto: stream truncated: %w", io.ErrUnexpectedEOF)
+	default:
+		return err
+	}
+
+	r.buf, err = r.aead.Open(r.buf[:0], r.chunkNonce(), r.sealed[:n], chunkAdditionalData(last))
+	if err != nil {
+		return fmt.Errorf("crypto: decryption failed: %w", err)
+	}
+	r.out = r.buf
+	r.done = last
+	return nil
+}
@@ -0,0 +1,285 @@
+// Package crypto implements the encryption of the messages and files sent
+// through PubNub.
+//
+// A CryptoModule encrypts with one Cryptor and decrypts with any of the
+// cryptors it knows: the data encrypted by a Cryptor other than the legacy
+// one starts with a header identifying it.
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	sentinel       = "PNED"
+	headerVersion  = 1
+	idLength       = 4
+	maxMetadataLen = 0xFFFF
+)
+
+var (
+	// ErrUnknownCryptor is returned when the data was encrypted by a cryptor
+	// the CryptoModule doesn't know.
+	ErrUnknownCryptor = errors.New("crypto: unknown cryptor")
+
+	// ErrUnsupportedVersion is returned when the header of the data has a
+	// version newer than the supported one.
+	ErrUnsupportedVersion = errors.New("crypto: unsupported header version")
+
+	// ErrMalformedHeader is returned when the header of the data is
+	// truncated.
+	ErrMalformedHeader = errors.New("crypto: malformed header")
+)
+
+// EncryptedData is the data encrypted by a Cryptor, Metadata is stored in
+// the header, the IV or nonce for example.
+type EncryptedData struct {
+	Metadata []byte
+	Data     []byte
+}
+
+// EncryptedStreamData is a stream encrypted by a Cryptor.
+type EncryptedStreamData struct {
+	Metadata []byte
+	Reader   io.Reader
+}
+
+// Cryptor is an encryption algorithm. ID identifies it in the header of the
+// encrypted data, it's 4 bytes long.
+type Cryptor interface {
+	ID() string
+	Encrypt(data []byte) (*EncryptedData, error)
+	Decrypt(data *EncryptedData) ([]byte, error)
+	EncryptStream(reader io.Reader) (*EncryptedStreamData, error)
+	DecryptStream(data *EncryptedStreamData) (io.Reader, error)
+}
+
+// CryptoModule encrypts and decrypts the messages and files. The streams
+// encrypted by EncryptStream are decrypted by DecryptStream, not Decrypt.
+type CryptoModule interface {
+	Encrypt(data []byte) ([]byte, error)
+	Decrypt(data []byte) ([]byte, error)
+	EncryptStream(reader io.Reader) (io.Reader, error)
+	DecryptStream(reader io.Reader) (io.Reader, error)
+}
+
+type module struct {
+	encryptor Cryptor
+	cryptors  map[string]Cryptor
+}
+
+// NewCryptoModule returns a CryptoModule encrypting with encryptor and
+// decrypting with encryptor or decryptors.
+func NewCryptoModule(encryptor Cryptor, decryptors ...Cryptor) CryptoModule {
+	m := &module{
+		encryptor: encryptor,
+		cryptors:  make(map[string]Cryptor, len(decryptors)+1),
+	}
+	for _, cryptor := range append([]Cryptor{encryptor}, decryptors...) {
+		m.cryptors[cryptor.ID()] = cryptor
+	}
+	return m
+}
+
+// NewLegacyCryptoModule returns a CryptoModule encrypting with AES-CBC like
+// the previous versions of the SDK, it decrypts the AES-GCM data too.
+func NewLegacyCryptoModule(cipherKey string, useRandomInitializationVector bool) (CryptoModule, error) {
+	legacy, err := NewLegacyCryptor(cipherKey, useRandomInitializationVector)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := NewAesGcmCryptor(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewCryptoModule(legacy, gcm), nil
+}
+
+// NewAesGcmCryptoModule returns a CryptoModule encrypting with AES-256-GCM,
+// it decrypts the legacy AES-CBC data too.
+func NewAesGcmCryptoModule(cipherKey string, useRandomInitializationVector bool) (CryptoModule, error) {
+	gcm, err := NewAesGcmCryptor(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := NewLegacyCryptor(cipherKey, useRandomInitializationVector)
+	if err != nil {
+		return nil, err
+	}
+	return NewCryptoModule(gcm, legacy), nil
+}
+
+func (m *module) Encrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("crypto: data is empty")
+	}
+	encrypted, err := m.encryptor.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.encryptor.ID() == legacyID {
+		return encrypted.Data, nil
+	}
+
+	header, err := encodeHeader(m.encryptor.ID(), encrypted.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return append(header, encrypted.Data...), nil
+}
+
+func (m *module) Decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("crypto: data is empty")
+	}
+
+	id, metadata := legacyID, []byte(nil)
+	if bytes.HasPrefix(data, []byte(sentinel)) {
+		r := bytes.NewReader(data[len(sentinel):])
+		var err error
+		if id, metadata, err = readHeader(r); err != nil {
+			return nil, err
+		}
+		data = data[len(data)-r.Len():]
+	}
+
+	cryptor, err := m.cryptor(id)
+	if err != nil {
+		return nil, err
+	}
+	return cryptor.Decrypt(&EncryptedData{Metadata: metadata, Data: data})
+}
+
+func (m *module) EncryptStream(reader io.Reader) (io.Reader, error) {
+	encrypted, err := m.encryptor.EncryptStream(reader)
+	if err != nil {
+		return nil, err
+	}
+	if m.encryptor.ID() == legacyID {
+		return encrypted.Reader, nil
+	}
+
+	header, err := encodeHeader(m.encryptor.ID(), encrypted.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return io.MultiReader(bytes.NewReader(header), encrypted.Reader), nil
+}
+
+func (m *module) DecryptStream(reader io.Reader) (io.Reader, error) {
+	r := bufio.NewReader(reader)
+	id, metadata := legacyID, []byte(nil)
+	if prefix, _ := r.Peek(len(sentinel)); string(prefix) == sentinel {
+		r.Discard(len(sentinel))
+		var err error
+		if id, metadata, err = readHeader(r); err != nil {
+			return nil, err
+		}
+	}
+
+	cryptor, err := m.cryptor(id)
+	if err != nil {
+		return nil, err
+	}
+	return cryptor.DecryptStream(&EncryptedStreamData{Metadata: metadata, Reader: r})
+}
+
+// StreamSizer is implemented by the cryptors knowing the size of the streams
+// they encrypt. The files are only uploaded encrypted by them, the length of
+// an upload is required before it starts.
+type StreamSizer interface {
+	// EncryptedStreamSize returns the length of the metadata and of the data
+	// of an encrypted stream of size bytes.
+	EncryptedStreamSize(size int64) (int, int64)
+}
+
+// EncryptedStreamSize returns the size of a stream of size bytes once
+// encrypted by the EncryptStream of cryptoModule. It's false when the size
+// isn't known in advance: the module isn't built by NewCryptoModule or its
+// encryptor isn't a StreamSizer.
+func EncryptedStreamSize(cryptoModule CryptoModule, size int64) (int64, bool) {
+	m, ok := cryptoModule.(*module)
+	if !ok {
+		return 0, false
+	}
+	sizer, ok := m.encryptor.(StreamSizer)
+	if !ok {
+		return 0, false
+	}
+	metadata, data := sizer.EncryptedStreamSize(size)
+	if m.encryptor.ID() == legacyID {
+		return data, true
+	}
+	return int64(headerSize(metadata)) + data, true
+}
+
+func (m *module) cryptor(id string) (Cryptor, error) {
+	cryptor, ok := m.cryptors[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCryptor, id)
+	}
+	return cryptor, nil
+}
+
+// encodeHeader returns the header of the data encrypted by the cryptor id:
+// the sentinel, the version, the cryptor id, the metadata length on 1 byte,
+// or 0xFF and 2 bytes once it's 255 or more, and the metadata.
+func encodeHeader(id string, metadata []byte) ([]byte, error) {
+	if len(id) != idLength {
+		return nil, fmt.Errorf("crypto: invalid cryptor id %q", id)
+	}
+	if len(metadata) > maxMetadataLen {
+		return nil, fmt.Errorf("crypto: metadata too large: %d bytes", len(metadata))
+	}
+
+	header := make([]byte, 0, headerSize(len(metadata)))
+	header = append(header, sentinel...)
+	header = append(header, headerVersion)
+	header = append(header, id...)
+	if len(metadata) < 0xFF {
+		header = append(header, byte(len(metadata)))
+	} else {
+		header = append(header, 0xFF, byte(len(metadata)>>8), byte(len(metadata)))
+	}
+	return append(header, metadata...), nil
+}
+
+// headerSize returns the length of a header with metadata bytes of metadata.
+func headerSize(metadata int) int {
+	size := len(sentinel) + 1 + idLength + 1 + metadata
+	if metadata >= 0xFF {
+		size += 2
+	}
+	return size
+}
+
+// readHeader reads the header following the sentinel.
+func readHeader(r io.Reader) (string, []byte, error) {
+	fixed := make([]byte, 1+idLength+1)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return "", nil, ErrMalformedHeader
+	}
+	if fixed[0] > headerVersion {
+		return "", nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, fixed[0])
+	}
+	id := string(fixed[1 : 1+idLength])
+
+	length := int(fixed[1+idLength])
+	if length == 0xFF {
+		size := make([]byte, 2)
+		if _, err := io.ReadFull(r, size); err != nil {
+			return "", nil, ErrMalformedHeader
+		}
+		length = int(size[0])<<8 | int(size[1])
+	}
+
+	metadata := make([]byte, length)
+	if _, err := io.ReadFull(r, metadata); err != nil {
+		return "", nil, ErrMalformedHeader
+	}
+	return id, metadata, nil
+}
@@ -0,0 +1,164 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/pubnub/go/v7/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, size := range []int{0, 12, 254, 255, 1000} {
+		metadata := bytes.Repeat([]byte{7}, size)
+		header, err := encodeHeader(aesGcmID, metadata)
+		assert.Nil(err)
+		assert.True(bytes.HasPrefix(header, []byte("PNED\x01AGCM")))
+
+		id, decoded, err := readHeader(bytes.NewReader(header[len(sentinel):]))
+		assert.Nil(err)
+		assert.Equal(aesGcmID, id)
+		assert.Equal(metadata, decoded)
+	}
+
+	_, err := encodeHeader("AG", nil)
+	assert.NotNil(err)
+	_, _, err = readHeader(bytes.NewReader([]byte{1, 'A', 'G'}))
+	assert.Equal(ErrMalformedHeader, err)
+}
+
+func TestLegacyCryptoModule(t *testing.T) {
+	assert := assert.New(t)
+	module, err := NewLegacyCryptoModule("enigma", false)
+	assert.Nil(err)
+
+	// the data is the same as the previous versions of the SDK, without header
+	encrypted, err := module.Encrypt([]byte(`"yay!"`))
+	assert.Nil(err)
+	assert.Equal(utils.EncryptString("enigma", `"yay!"`, false), base64.StdEncoding.EncodeToString(encrypted))
+
+	decrypted, err := module.Decrypt(encrypted)
+	assert.Nil(err)
+	assert.Equal(`"yay!"`, string(decrypted))
+
+	_, err = NewLegacyCryptoModule("", false)
+	assert.NotNil(err)
+}
+
+func TestAesGcmCryptoModule(t *testing.T) {
+	assert := assert.New(t)
+	module, err := NewAesGcmCryptoModule("enigma", true)
+	assert.Nil(err)
+
+	encrypted, err := module.Encrypt([]byte("hello"))
+	assert.Nil(err)
+	assert.True(bytes.HasPrefix(encrypted, []byte("PNED\x01AGCM\x0c")))
+
+	decrypted, err := module.Decrypt(encrypted)
+	assert.Nil(err)
+	assert.Equal("hello", string(decrypted))
+
+	// the header picks the cryptor of either module
+	legacy, err := NewLegacyCryptoModule("enigma", true)
+	assert.Nil(err)
+	decrypted, err = legacy.Decrypt(encrypted)
+	assert.Nil(err)
+	assert.Equal("hello", string(decrypted))
+
+	legacyEncrypted, err := legacy.Encrypt([]byte("hello"))
+	assert.Nil(err)
+	decrypted, err = module.Decrypt(legacyEncrypted)
+	assert.Nil(err)
+	assert.Equal("hello", string(decrypted))
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = module.Decrypt(tampered)
+	assert.NotNil(err)
+
+	other, err := NewAesGcmCryptoModule("other", true)
+	assert.Nil(err)
+	_, err = other.Decrypt(encrypted)
+	assert.NotNil(err)
+}
+
+func TestCryptoModuleUnknownHeader(t *testing.T) {
+	assert := assert.New(t)
+	gcm, err := NewAesGcmCryptor("enigma")
+	assert.Nil(err)
+	module := NewCryptoModule(gcm)
+
+	_, err = module.Decrypt([]byte("PNED\x01ABCD\x00data"))
+	assert.True(errors.Is(err, ErrUnknownCryptor))
+	_, err = module.Decrypt([]byte("PNED\x02AGCM\x00data"))
+	assert.True(errors.Is(err, ErrUnsupportedVersion))
+	_, err = module.Decrypt([]byte("PNED\x01AG"))
+	assert.Equal(ErrMalformedHeader, err)
+
+	// without a legacy cryptor the data without header can't be decrypted
+	_, err = module.Decrypt([]byte("0123456789012345"))
+	assert.True(errors.Is(err, ErrUnknownCryptor))
+	_, err = module.Encrypt(nil)
+	assert.NotNil(err)
+}
+
+func TestCryptoModuleStream(t *testing.T) {
+	assert := assert.New(t)
+	gcm, err := NewAesGcmCryptoModule("enigma", false)
+	assert.Nil(err)
+	legacy, err := NewLegacyCryptoModule("enigma", false)
+	assert.Nil(err)
+
+	for _, size := range []int{0, 1, 16, gcmChunkSize - 1, gcmChunkSize, gcmChunkSize + 1, 3 * gcmChunkSize} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+
+		for _, module := range []CryptoModule{gcm, legacy} {
+			reader, err := module.EncryptStream(bytes.NewReader(data))
+			assert.Nil(err)
+			encrypted, err := ioutil.ReadAll(reader)
+			assert.Nil(err)
+			encryptedSize, ok := EncryptedStreamSize(module, int64(size))
+			assert.True(ok)
+			assert.Equal(int64(len(encrypted)), encryptedSize, size)
+
+			for _, decryptor := range []CryptoModule{gcm, legacy} {
+				reader, err = decryptor.DecryptStream(bytes.NewReader(encrypted))
+				if !assert.Nil(err, size) {
+					continue
+				}
+				decrypted, err := ioutil.ReadAll(reader)
+				assert.Nil(err, size)
+				assert.Equal(data, decrypted, size)
+			}
+		}
+	}
+}
+
+func TestAesGcmStreamTruncated(t *testing.T) {
+	assert := assert.New(t)
+	module, err := NewAesGcmCryptoModule("enigma", false)
+	assert.Nil(err)
+
+	data := make([]byte, 2*gcmChunkSize+10)
+	reader, err := module.EncryptStream(bytes.NewReader(data))
+	assert.Nil(err)
+	encrypted, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+
+	header := len("PNED\x01AGCM\x0c") + 12
+	sealedChunk := gcmChunkSize + 16
+	for _, size := range []int{header, header + sealedChunk, header + 2*sealedChunk, len(encrypted) - 1} {
+		reader, err = module.DecryptStream(bytes.NewReader(encrypted[:size]))
+		assert.Nil(err)
+		_, err = ioutil.ReadAll(reader)
+		assert.NotNil(err, size)
+	}
+}
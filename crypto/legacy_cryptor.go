@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"errors"
+	"io"
+
+	"github.com/pubnub/go/v7/utils"
+)
+
+const legacyID = "0000"
+
+// LegacyCryptor is AES-CBC with the key derived from the cipher key like the
+// previous versions of the SDK. Its data has no header.
+type LegacyCryptor struct {
+	cipherKey string
+	randomIV  bool
+}
+
+// NewLegacyCryptor returns a LegacyCryptor. The IV of the messages is random
+// and prepended to them when useRandomInitializationVector is set, the IV of
+// the streams always is.
+func NewLegacyCryptor(cipherKey string, useRandomInitializationVector bool) (*LegacyCryptor, error) {
+	if cipherKey == "" {
+		return nil, errors.New("crypto: cipher key is empty")
+	}
+	return &LegacyCryptor{
+		cipherKey: cipherKey,
+		randomIV:  useRandomInitializationVector,
+	}, nil
+}
+
+// ID returns the id of the legacy cryptor, it's never written in a header.
+func (c *LegacyCryptor) ID() string {
+	return legacyID
+}
+
+// Encrypt encrypts data.
+func (c *LegacyCryptor) Encrypt(data []byte) (*EncryptedData, error) {
+	encrypted, err := utils.EncryptBytes(c.cipherKey, data, c.randomIV)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedData{Data: encrypted}, nil
+}
+
+// Decrypt decrypts data.
+func (c *LegacyCryptor) Decrypt(data *EncryptedData) ([]byte, error) {
+	return utils.DecryptBytes(c.cipherKey, data.Data, c.randomIV)
+}
+
+// EncryptStream encrypts the stream with a random IV as it's read.
+func (c *LegacyCryptor) EncryptStream(reader io.Reader) (*EncryptedStreamData, error) {
+	encrypted, err := utils.NewEncryptReader(c.cipherKey, nil, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedStreamData{Reader: encrypted}, nil
+}
+
+// DecryptStream decrypts the stream encrypted by EncryptStream as it's read.
+func (c *LegacyCryptor) DecryptStream(data *EncryptedStreamData) (io.Reader, error) {
+	return utils.NewDecryptReader(c.cipherKey, data.Reader)
+}
+
+// EncryptedStreamSize returns the size of the IV and of the padded stream.
+func (c *LegacyCryptor) EncryptedStreamSize(size int64) (int, int64) {
+	return 0, aes.BlockSize + (size/aes.BlockSize+1)*aes.BlockSize
+}
@@ -0,0 +1,52 @@
+package pubnub
+
+import (
+	"encoding/base64"
+
+	"github.com/pubnub/go/v7/crypto"
+)
+
+// cryptoModule returns Config.CryptoModule, or the legacy module of CipherKey
+// when it's not set. It's nil when nothing is encrypted.
+func (c *Config) cryptoModule() crypto.CryptoModule {
+	if c.CryptoModule != nil {
+		return c.CryptoModule
+	}
+	if c.CipherKey == "" {
+		return nil
+	}
+	module, _ := crypto.NewLegacyCryptoModule(c.CipherKey, c.UseRandomInitializationVector)
+	return module
+}
+
+// fileCryptoModule returns the module of the files, the cipherKey set on a
+// files request overrides the config.
+func fileCryptoModule(cipherKey string, c *Config) crypto.CryptoModule {
+	if cipherKey == "" {
+		return c.cryptoModule()
+	}
+	module, _ := crypto.NewLegacyCryptoModule(cipherKey, c.UseRandomInitializationVector)
+	return module
+}
+
+// encryptString encrypts message with module and base64 encodes it.
+func encryptString(module crypto.CryptoModule, message string) (string, error) {
+	encrypted, err := module.Encrypt([]byte(message))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptString decrypts the base64 encoded message with module.
+func decryptString(module crypto.CryptoModule, message string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(message)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := module.Decrypt(encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
@@ -0,0 +1,200 @@
+package pubnub
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/pubnub/go/v7/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAesGcmConfig(t *testing.T) *Config {
+	module, err := crypto.NewAesGcmCryptoModule("enigma", false)
+	assert.Nil(t, err)
+	config := NewDemoConfig()
+	config.CryptoModule = module
+	return config
+}
+
+func TestCryptoModulePublish(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(newAesGcmConfig(t))
+	defer pn.Destroy()
+
+	// the legacy module of CipherKey decrypts the AES-GCM messages too
+	legacyConfig := NewDemoConfig()
+	legacyConfig.CipherKey = "enigma"
+	legacy := NewPubNub(legacyConfig)
+	defer legacy.Destroy()
+
+	publish := pn.Publish().Channel("ch").Message(map[string]interface{}{"text": "hi"})
+	msg, err := publish.opts.message()
+	assert.Nil(err)
+
+	var encrypted string
+	assert.Nil(json.Unmarshal([]byte(msg), &encrypted))
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	assert.Nil(err)
+	assert.True(bytes.HasPrefix(data, []byte("PNED\x01AGCM")))
+
+	for _, config := range []*Config{pn.Config, legacyConfig} {
+		decrypted, err := parseCipherInterface(encrypted, config)
+		assert.Nil(err)
+		assert.Equal(map[string]interface{}{"text": "hi"}, decrypted)
+	}
+
+	publish = pn.Publish().Channel("ch").Message(map[string]interface{}{"pn_other": "secret", "pn_gcm": "clear"})
+	msg, err = publish.opts.message()
+	assert.Nil(err)
+	var message map[string]interface{}
+	assert.Nil(json.Unmarshal([]byte(msg), &message))
+	assert.Equal("clear", message["pn_gcm"])
+	decrypted, err := parseCipherInterface(message, legacyConfig)
+	assert.Nil(err)
+	assert.Equal("secret", decrypted.(map[string]interface{})["pn_other"])
+}
+
+func TestCryptoModuleFire(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(newAesGcmConfig(t))
+	defer pn.Destroy()
+
+	fire := pn.Fire().Channel("ch").Message("hi").UsePost(true)
+	body, err := fire.opts.buildBody()
+	assert.Nil(err)
+
+	var encrypted string
+	assert.Nil(json.Unmarshal(body, &encrypted))
+	decrypted, err := parseCipherInterface(encrypted, pn.Config)
+	assert.Nil(err)
+	assert.Equal("hi", decrypted)
+}
+
+func TestCryptoModuleSendFileToS3Body(t *testing.T) {
+	assert := assert.New(t)
+
+	file, err := ioutil.TempFile("", "pubnub-crypto")
+	assert.Nil(err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	content := bytes.Repeat([]byte("file content "), 1000)
+	_, err = file.Write(content)
+	assert.Nil(err)
+
+	for _, cipherKey := range []string{"", "other"} {
+		_, err = file.Seek(0, io.SeekStart)
+		assert.Nil(err)
+		pn := NewPubNub(newAesGcmConfig(t))
+		o := &sendFileToS3Opts{
+			pubnub:    pn,
+			File:      file,
+			CipherKey: cipherKey,
+			FileUploadRequestData: PNFileUploadRequest{
+				FormFields: []PNFormField{{Key: "key", Value: "value"}},
+			},
+		}
+
+		body, contentType, length, err := o.streamBodyMultipartFileUpload()
+		assert.Nil(err)
+		raw, err := ioutil.ReadAll(body)
+		assert.Nil(err)
+		// the length of the encrypted body is known in advance
+		assert.Equal(int64(len(raw)), length)
+		_, uploaded := readMultipartBody(t, bytes.NewReader(raw), contentType)
+
+		// the cipher key of the request overrides the module with a legacy one
+		module := fileCryptoModule(cipherKey, pn.Config)
+		assert.Equal(cipherKey == "", bytes.HasPrefix(uploaded, []byte("PNED")), cipherKey)
+		decrypted, err := module.DecryptStream(bytes.NewReader(uploaded))
+		if assert.Nil(err) {
+			plain, err := ioutil.ReadAll(decrypted)
+			assert.Nil(err)
+			assert.Equal(content, plain)
+		}
+		pn.Destroy()
+	}
+}
+
+func TestDownloadFileDecryption(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.CipherKey = "enigma"
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	content := bytes.Repeat([]byte("file content "), 100)
+	reader, err := pn.Config.cryptoModule().EncryptStream(bytes.NewReader(content))
+	assert.Nil(err)
+	encrypted, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+
+	var body []byte
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			Request:    req,
+		}, nil
+	})})
+
+	body = encrypted
+	resp, _, err := pn.DownloadFile().Channel("ch").ID("id").Name("name").Execute()
+	assert.Nil(err)
+	downloaded, err := ioutil.ReadAll(resp.File)
+	assert.Nil(err)
+	assert.Equal(content, downloaded)
+	_, ok := resp.File.(io.Closer)
+	assert.True(ok)
+
+	// the corruption is returned by the reader
+	body = append([]byte{}, encrypted...)
+	body[len(body)-1] ^= 1
+	resp, _, err = pn.DownloadFile().Channel("ch").ID("id").Name("name").Execute()
+	assert.Nil(err)
+	_, err = ioutil.ReadAll(resp.File)
+	assert.NotNil(err)
+}
+
+func TestDownloadFileProgress(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(newAesGcmConfig(t))
+	defer pn.Destroy()
+
+	content := bytes.Repeat([]byte("file content "), 10000)
+	reader, err := pn.Config.cryptoModule().EncryptStream(bytes.NewReader(content))
+	assert.Nil(err)
+	encrypted, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    200,
+			ContentLength: int64(len(encrypted)),
+			Body:          ioutil.NopCloser(bytes.NewReader(encrypted)),
+			Request:       req,
+		}, nil
+	})})
+
+	var progress []PNFileTransferProgress
+	resp, _, err := pn.DownloadFile().Channel("ch").ID("id").Name("name").
+		Progress(func(p PNFileTransferProgress) {
+			progress = append(progress, p)
+		}).Execute()
+	assert.Nil(err)
+	downloaded, err := ioutil.ReadAll(resp.File)
+	assert.Nil(err)
+	assert.Equal(content, downloaded)
+
+	// the bytes downloaded are the encrypted ones
+	total := int64(len(encrypted))
+	if assert.True(len(progress) > 1) {
+		assert.Equal(PNFileTransferProgress{Phase: PNFileTransferDownloading, Total: total}, progress[0])
+		assert.Equal(PNFileTransferProgress{Phase: PNFileTransferDownloading, Transferred: total, Total: total}, progress[len(progress)-1])
+	}
+}
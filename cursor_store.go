@@ -0,0 +1,122 @@
+package pubnub
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SubscribeCursor is the position of the subscribe loop, the timetoken and
+// the region to continue subscribing from.
+type SubscribeCursor struct {
+	Timetoken int64 `json:"timetoken"`
+	Region    int8  `json:"region"`
+}
+
+// CursorStore persists the subscribe cursor, see Config.CursorStore.
+// The SDK saves the cursor after the messages of every subscribe response
+// were processed and loads it on the first Subscribe call.
+type CursorStore interface {
+	// Load returns the stored cursor, or nil if there is none.
+	Load() (*SubscribeCursor, error)
+	// Save stores the cursor.
+	Save(cursor SubscribeCursor) error
+}
+
+// MemoryCursorStore keeps the subscribe cursor in memory, it's useful to
+// resume subscribe with a new PubNub instance in the same process.
+type MemoryCursorStore struct {
+	sync.RWMutex
+	cursor *SubscribeCursor
+}
+
+// NewMemoryCursorStore creates an empty MemoryCursorStore.
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{}
+}
+
+// Load returns the stored cursor, or nil if there is none.
+func (s *MemoryCursorStore) Load() (*SubscribeCursor, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.cursor == nil {
+		return nil, nil
+	}
+	cursor := *s.cursor
+	return &cursor, nil
+}
+
+// Save stores the cursor.
+func (s *MemoryCursorStore) Save(cursor SubscribeCursor) error {
+	s.Lock()
+	s.cursor = &cursor
+	s.Unlock()
+
+	return nil
+}
+
+// FileCursorStore keeps the subscribe cursor in a JSON file, to resume
+// subscribe after a restart of the process.
+type FileCursorStore struct {
+	sync.Mutex
+	path string
+}
+
+// NewFileCursorStore creates a FileCursorStore writing to path.
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{path: path}
+}
+
+// Load reads the cursor from the file, it returns nil if the file doesn't exist.
+func (s *FileCursorStore) Load() (*SubscribeCursor, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor SubscribeCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// Save writes the cursor to the file. The file is replaced atomically, a
+// crash while saving leaves the previous cursor in place.
+func (s *FileCursorStore) Save(cursor SubscribeCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package pubnub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCursorStore(t *testing.T) {
+	assert := assert.New(t)
+	store := NewMemoryCursorStore()
+
+	cursor, err := store.Load()
+	assert.Nil(err)
+	assert.Nil(cursor)
+
+	assert.Nil(store.Save(SubscribeCursor{Timetoken: 15000000000000000, Region: 4}))
+	cursor, err = store.Load()
+	assert.Nil(err)
+	assert.Equal(&SubscribeCursor{Timetoken: 15000000000000000, Region: 4}, cursor)
+}
+
+func TestFileCursorStore(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "cursor")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cursor.json")
+	store := NewFileCursorStore(path)
+
+	cursor, err := store.Load()
+	assert.Nil(err)
+	assert.Nil(cursor)
+
+	assert.Nil(store.Save(SubscribeCursor{Timetoken: 15000000000000000, Region: 4}))
+	assert.Nil(store.Save(SubscribeCursor{Timetoken: 15000000000000001, Region: 7}))
+
+	cursor, err = NewFileCursorStore(path).Load()
+	assert.Nil(err)
+	assert.Equal(&SubscribeCursor{Timetoken: 15000000000000001, Region: 7}, cursor)
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.Equal(1, len(files))
+
+	assert.Nil(ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = store.Load()
+	assert.NotNil(err)
+}
+
+func TestSubscribeResumesFromCursorStore(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/subscribe/demo/ch/0",
+		Query:              "",
+		ResponseBody:       `{"t":{"t":"15000000000000000","r":12},"m":[]}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "heartbeat", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/subscribe/demo/ch/0",
+		Query:              "tt=14000000000000000&tr=4",
+		ResponseBody:       `{"t":{"t":"16000000000000000","r":12},"m":[{"a":"1","f":0,"i":"publisher","p":{"t":"15500000000000000","r":12},"k":"demo","c":"ch","d":"hi","b":"ch"}]}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "heartbeat", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+
+	// keeps the subscribe loop running until the test is done
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/subscribe/demo/ch/0",
+		Query:              "tt=16000000000000000&tr=12",
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "heartbeat", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+		Hang:               true,
+	})
+
+	store := NewMemoryCursorStore()
+	store.Save(SubscribeCursor{Timetoken: 14000000000000000, Region: 4})
+
+	config := NewDemoConfig()
+	config.CursorStore = store
+	pn := NewPubNub(config)
+	pn.SetSubscribeClient(interceptor.GetClient())
+	pn.SetClient(interceptor.GetClient())
+	defer pn.Destroy()
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	pn.Subscribe().Channels([]string{"ch"}).Execute()
+
+	select {
+	case m := <-listener.Message:
+		assert.Equal("hi", m.Message)
+	case <-time.After(5 * time.Second):
+		assert.Fail("message after the stored cursor not delivered")
+		return
+	}
+
+	deadline := time.After(5 * time.Second)
+	for {
+		cursor, _ := store.Load()
+		if cursor.Timetoken == 16000000000000000 {
+			assert.Equal(int8(12), cursor.Region)
+			return
+		}
+		select {
+		case <-deadline:
+			assert.Fail("cursor not saved", "%v", cursor)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
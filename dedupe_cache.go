@@ -0,0 +1,67 @@
+package pubnub
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// dedupeCache remembers the recently announced messages to suppress the ones
+// delivered twice by subscribe, e.g. after a reconnection or a timetoken reset.
+// It's bounded both by the number of entries and their age.
+type dedupeCache struct {
+	sync.Mutex
+
+	entries    map[string]*list.Element
+	order      *list.List
+	suppressed int64
+}
+
+type dedupeEntry struct {
+	key  string
+	seen time.Time
+}
+
+func newDedupeCache() *dedupeCache {
+	return &dedupeCache{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// isDuplicate reports whether key was already seen within ttl and records it
+// otherwise. At most size entries are kept, the oldest ones are evicted first.
+func (c *dedupeCache) isDuplicate(key string, size int, ttl time.Duration, now time.Time) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	for e := c.order.Front(); e != nil; e = c.order.Front() {
+		entry := e.Value.(*dedupeEntry)
+		if ttl <= 0 || now.Sub(entry.seen) < ttl {
+			break
+		}
+		c.order.Remove(e)
+		delete(c.entries, entry.key)
+	}
+
+	if _, ok := c.entries[key]; ok {
+		c.suppressed++
+		return true
+	}
+
+	c.entries[key] = c.order.PushBack(&dedupeEntry{key: key, seen: now})
+	for c.order.Len() > size {
+		e := c.order.Front()
+		c.order.Remove(e)
+		delete(c.entries, e.Value.(*dedupeEntry).key)
+	}
+
+	return false
+}
+
+func (c *dedupeCache) suppressedCount() int64 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.suppressed
+}
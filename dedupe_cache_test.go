@@ -0,0 +1,86 @@
+package pubnub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupeCacheSuppressesDuplicates(t *testing.T) {
+	assert := assert.New(t)
+	c := newDedupeCache()
+	now := time.Now()
+
+	assert.False(c.isDuplicate("1/ch/a", 10, time.Minute, now))
+	assert.False(c.isDuplicate("1/ch/b", 10, time.Minute, now))
+	assert.True(c.isDuplicate("1/ch/a", 10, time.Minute, now))
+	assert.Equal(int64(1), c.suppressedCount())
+}
+
+func TestDedupeCacheBoundedBySize(t *testing.T) {
+	assert := assert.New(t)
+	c := newDedupeCache()
+	now := time.Now()
+
+	assert.False(c.isDuplicate("1", 2, time.Minute, now))
+	assert.False(c.isDuplicate("2", 2, time.Minute, now))
+	assert.False(c.isDuplicate("3", 2, time.Minute, now))
+	assert.Equal(2, c.order.Len())
+
+	// "1" was evicted
+	assert.False(c.isDuplicate("1", 2, time.Minute, now))
+	assert.True(c.isDuplicate("3", 2, time.Minute, now))
+}
+
+func TestDedupeCacheBoundedByAge(t *testing.T) {
+	assert := assert.New(t)
+	c := newDedupeCache()
+	now := time.Now()
+
+	assert.False(c.isDuplicate("1", 10, time.Minute, now))
+	assert.True(c.isDuplicate("1", 10, time.Minute, now.Add(30*time.Second)))
+	assert.False(c.isDuplicate("1", 10, time.Minute, now.Add(2*time.Minute)))
+	assert.Equal(1, len(c.entries))
+}
+
+func TestSubscribeSuppressesDuplicateMessages(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.DedupeCacheSize = 100
+	pn := NewPubNub(config)
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	message := subscribeMessage{
+		Channel:         "ch",
+		Payload:         "hi",
+		IssuingClientID: "publisher",
+		PublishMetaData: publishMetadata{PublishTimetoken: "15000000000000000"},
+	}
+	processSubscribePayload(pn.subscriptionManager, message)
+	processSubscribePayload(pn.subscriptionManager, message)
+	message.Payload = "next"
+	message.PublishMetaData.PublishTimetoken = "15000000000000001"
+	processSubscribePayload(pn.subscriptionManager, message)
+
+	for _, expected := range []string{"hi", "next"} {
+		select {
+		case m := <-listener.Message:
+			assert.Equal(expected, m.Message)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message not delivered")
+			return
+		}
+	}
+	assert.Equal(int64(1), pn.GetSuppressedDuplicates())
+}
+
+func TestSubscribeDedupeDisabled(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	assert.False(pn.subscriptionManager.isDuplicate("ch", "1", "a"))
+	assert.False(pn.subscriptionManager.isDuplicate("ch", "1", "a"))
+	assert.Equal(int64(0), pn.GetSuppressedDuplicates())
+}
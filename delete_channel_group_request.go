@@ -55,6 +55,13 @@ func (b *deleteChannelGroupBuilder) QueryParam(queryParam map[string]string) *de
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *deleteChannelGroupBuilder) RetryPolicy(policy RetryPolicy) *deleteChannelGroupBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the DeleteChannelGroup request.
 func (b *deleteChannelGroupBuilder) Execute() (
 	*DeleteChannelGroupResponse, StatusResponse, error) {
@@ -72,6 +79,7 @@ type deleteChannelGroupOpts struct {
 	ChannelGroup string
 	Transport    http.RoundTripper
 	QueryParam   map[string]string
+	RetryPolicy  *RetryPolicy
 	ctx          Context
 }
 
@@ -153,3 +161,14 @@ func (o *deleteChannelGroupOpts) telemetryManager() *TelemetryManager {
 func (o *deleteChannelGroupOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *deleteChannelGroupOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *deleteChannelGroupOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
@@ -29,6 +29,8 @@ type endpointOpts interface {
 	operationType() OperationType
 	telemetryManager() *TelemetryManager
 	tokenManager() *TokenManager
+	originManager() *OriginManager
+	retryPolicy() RetryPolicy
 }
 
 // SetQueryParam appends the query params map to the query string
@@ -175,10 +177,10 @@ func buildURL(o endpointOpts) (*url.URL, error) {
 
 	scheme := fmt.Sprintf("http%s", secure)
 
-	host := o.config().Origin
+	host := o.originManager().currentOrigin()
 
 	if o.httpMethod() != "POSTFORM" {
-		path = fmt.Sprintf("//%s%s", o.config().Origin, path)
+		path = fmt.Sprintf("//%s%s", host, path)
 	} else {
 		p := strings.Split(path, "://")
 		scheme = p[0]
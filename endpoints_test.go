@@ -73,6 +73,14 @@ func (o *fakeEndpointOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *fakeEndpointOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *fakeEndpointOpts) retryPolicy() RetryPolicy {
+	return RetryPolicy{}
+}
+
 func TestSignatureV2(t *testing.T) {
 	assert := assert.New(t)
 	httpMethod := "POST"
@@ -19,6 +19,21 @@ type ReconnectionPolicy int
 // PNPushType is used as an enum to catgorize the available Push Types
 type PNPushType int
 
+// ListenerOverflowPolicy is used as an enum to catgorize the behaviour of a full listener queue
+type ListenerOverflowPolicy int
+
+// RateLimitMode is used as an enum to catgorize the behaviour of the publish rate limiter
+type RateLimitMode int
+
+// SubscribeState is used as an enum to catgorize the states of the subscribe loop
+type SubscribeState int
+
+// FileTransferPhase is used as an enum to catgorize the phases of a file transfer
+type FileTransferPhase int
+
+// SendFileStep is used as an enum to catgorize the steps of the SendFile workflow
+type SendFileStep int
+
 // PNUUIDMetadataInclude is used as an enum to catgorize the available UUID include types
 type PNUUIDMetadataInclude int
 
@@ -177,6 +192,79 @@ const (
 	PNReconnectionAttemptsExhausted
 	// PNRequestMessageCountExceededCategory is fired when the MessageQueueOverflowCount limit is exceeded by the number of messages received in a single subscribe request
 	PNRequestMessageCountExceededCategory
+	// PNDNSFailureCategory as the StatusCategory means the host lookup of the origin failed.
+	PNDNSFailureCategory
+	// PNTLSFailureCategory as the StatusCategory means the TLS handshake or the certificate verification failed.
+	PNTLSFailureCategory
+	// PNTooManyRequestsCategory as the StatusCategory means the server responded with 429 (rate limited).
+	PNTooManyRequestsCategory
+	// PNServerErrorCategory as the StatusCategory means the server responded with a 5xx status code.
+	PNServerErrorCategory
+	// PNMalformedResponseCategory as the StatusCategory means the response of the server could not be read or parsed.
+	PNMalformedResponseCategory
+	// PNListenerQueueOverflowCategory as the StatusCategory means events were dropped because the listener queue was full.
+	// Applicable only for PNListenerOverflowStatus.
+	PNListenerQueueOverflowCategory
+	// PNSequenceGapCategory as the StatusCategory means messages of a publisher were lost, the ErrorData is a *PNSequenceGapError.
+	// Applicable only when Config.DetectSequenceGaps is set.
+	PNSequenceGapCategory
+	// PNTLSPinningFailureCategory as the StatusCategory means no certificate of the origin matches Config.TLSPinnedKeys, the ErrorData wraps a *pnerr.PinningError.
+	PNTLSPinningFailureCategory
+)
+
+const (
+	// PNListenerOverflowBlock blocks the announcement of new events until the listener catches up.
+	PNListenerOverflowBlock ListenerOverflowPolicy = 1 + iota
+	// PNListenerOverflowDropOldest drops the oldest queued event to make room for the new one.
+	PNListenerOverflowDropOldest
+	// PNListenerOverflowDropNewest drops the new event.
+	PNListenerOverflowDropNewest
+	// PNListenerOverflowStatus drops the new event and announces a PNListenerQueueOverflowCategory status to the listener once there is room again.
+	PNListenerOverflowStatus
+)
+
+const (
+	// PNRateLimitContext waits for the rate limit unless the context of the request is done first. Requests without a context wait like PNRateLimitBlock.
+	PNRateLimitContext RateLimitMode = 1 + iota
+	// PNRateLimitBlock waits for the rate limit, ignoring the context of the request.
+	PNRateLimitBlock
+	// PNRateLimitFailFast fails the requests exceeding the rate limit with a *pnerr.RateLimitError and a PNTooManyRequestsCategory status.
+	PNRateLimitFailFast
+)
+
+const (
+	// PNSubscribeStateStopped is the state of the subscribe loop when there is nothing to subscribe to or the loop was cancelled.
+	PNSubscribeStateStopped SubscribeState = 1 + iota
+	// PNSubscribeStateHandshaking is the state of the subscribe loop while it waits for the first response after (re)starting.
+	PNSubscribeStateHandshaking
+	// PNSubscribeStateReceiving is the state of the subscribe loop once the server has responded and messages are being received.
+	PNSubscribeStateReceiving
+	// PNSubscribeStateReconnecting is the state of the subscribe loop after a recoverable failure, while it tries to restore the connection.
+	PNSubscribeStateReconnecting
+	// PNSubscribeStateFailed is the state of the subscribe loop after a non recoverable failure. A new Subscribe call is required to leave it.
+	PNSubscribeStateFailed
+)
+
+const (
+	// PNFileTransferRequestingURL is the phase of SendFile requesting the upload URL of the file.
+	PNFileTransferRequestingURL FileTransferPhase = 1 + iota
+	// PNFileTransferUploading is the phase of SendFile uploading the file to the storage.
+	PNFileTransferUploading
+	// PNFileTransferPublishing is the phase of SendFile publishing the file message once the file is uploaded.
+	PNFileTransferPublishing
+	// PNFileTransferDownloading is the phase of DownloadFile reading the file.
+	PNFileTransferDownloading
+)
+
+const (
+	// PNSendFileStepRequestURL is the first step of SendFile, requesting the upload URL of the file.
+	PNSendFileStepRequestURL SendFileStep = 1 + iota
+	// PNSendFileStepUpload is the step of SendFile uploading the file once its ID is known.
+	PNSendFileStepUpload
+	// PNSendFileStepPublish is the step of SendFile publishing the file message once the file is uploaded.
+	PNSendFileStepPublish
+	// PNSendFileStepDone is the step of SendFile once the file message is published.
+	PNSendFileStepDone
 )
 
 const (
@@ -447,12 +535,136 @@ func (c StatusCategory) String() string {
 	case PNNoStubMatchedCategory:
 		return "No Stub Matched"
 
+	case PNDNSFailureCategory:
+		return "DNS Failure"
+
+	case PNTLSFailureCategory:
+		return "TLS Failure"
+
+	case PNTooManyRequestsCategory:
+		return "Too Many Requests"
+
+	case PNServerErrorCategory:
+		return "Server Error"
+
+	case PNMalformedResponseCategory:
+		return "Malformed Response"
+
+	case PNListenerQueueOverflowCategory:
+		return "Listener Queue Overflow"
+
+	case PNSequenceGapCategory:
+		return "Sequence Gap"
+
+	case PNTLSPinningFailureCategory:
+		return "TLS Pinning Failure"
+
 	default:
 		return "No Stub Matched"
 
 	}
 }
 
+func (s SubscribeState) String() string {
+	switch s {
+	case PNSubscribeStateStopped:
+		return "Stopped"
+
+	case PNSubscribeStateHandshaking:
+		return "Handshaking"
+
+	case PNSubscribeStateReceiving:
+		return "Receiving"
+
+	case PNSubscribeStateReconnecting:
+		return "Reconnecting"
+
+	case PNSubscribeStateFailed:
+		return "Failed"
+
+	default:
+		return "No State Matched"
+
+	}
+}
+
+func (p FileTransferPhase) String() string {
+	switch p {
+	case PNFileTransferRequestingURL:
+		return "Requesting URL"
+
+	case PNFileTransferUploading:
+		return "Uploading"
+
+	case PNFileTransferPublishing:
+		return "Publishing"
+
+	case PNFileTransferDownloading:
+		return "Downloading"
+
+	default:
+		return "No Phase Matched"
+
+	}
+}
+
+func (s SendFileStep) String() string {
+	switch s {
+	case PNSendFileStepRequestURL:
+		return "Request URL"
+
+	case PNSendFileStepUpload:
+		return "Upload"
+
+	case PNSendFileStepPublish:
+		return "Publish"
+
+	case PNSendFileStepDone:
+		return "Done"
+
+	default:
+		return "No Step Matched"
+
+	}
+}
+
+func (m RateLimitMode) String() string {
+	switch m {
+	case PNRateLimitContext:
+		return "Context"
+
+	case PNRateLimitBlock:
+		return "Block"
+
+	case PNRateLimitFailFast:
+		return "Fail Fast"
+
+	default:
+		return "No Mode Matched"
+
+	}
+}
+
+func (p ListenerOverflowPolicy) String() string {
+	switch p {
+	case PNListenerOverflowBlock:
+		return "Block"
+
+	case PNListenerOverflowDropOldest:
+		return "Drop Oldest"
+
+	case PNListenerOverflowDropNewest:
+		return "Drop Newest"
+
+	case PNListenerOverflowStatus:
+		return "Status"
+
+	default:
+		return "No Policy Matched"
+
+	}
+}
+
 func (t OperationType) String() string {
 	switch t {
 	case PNSubscribeOperation:
@@ -37,6 +37,33 @@ func TestStatusCategoryString(t *testing.T) {
 	assert.Equal("Reconnected", PNReconnectedCategory.String())
 	assert.Equal("Reconnection Attempts Exhausted", PNReconnectionAttemptsExhausted.String())
 	assert.Equal("No Stub Matched", PNNoStubMatchedCategory.String())
+	assert.Equal("DNS Failure", PNDNSFailureCategory.String())
+	assert.Equal("TLS Failure", PNTLSFailureCategory.String())
+	assert.Equal("Too Many Requests", PNTooManyRequestsCategory.String())
+	assert.Equal("Server Error", PNServerErrorCategory.String())
+	assert.Equal("Malformed Response", PNMalformedResponseCategory.String())
+	assert.Equal("Listener Queue Overflow", PNListenerQueueOverflowCategory.String())
+	assert.Equal("Sequence Gap", PNSequenceGapCategory.String())
+	assert.Equal("TLS Pinning Failure", PNTLSPinningFailureCategory.String())
+}
+
+func TestRateLimitModeString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Context", PNRateLimitContext.String())
+	assert.Equal("Block", PNRateLimitBlock.String())
+	assert.Equal("Fail Fast", PNRateLimitFailFast.String())
+	assert.Equal("No Mode Matched", RateLimitMode(0).String())
+}
+
+func TestListenerOverflowPolicyString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Block", PNListenerOverflowBlock.String())
+	assert.Equal("Drop Oldest", PNListenerOverflowDropOldest.String())
+	assert.Equal("Drop Newest", PNListenerOverflowDropNewest.String())
+	assert.Equal("Status", PNListenerOverflowStatus.String())
+	assert.Equal("No Policy Matched", ListenerOverflowPolicy(0).String())
 }
 
 func TestOperationTypeString(t *testing.T) {
@@ -119,6 +119,13 @@ func (b *fetchBuilder) QueryParam(queryParam map[string]string) *fetchBuilder {
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *fetchBuilder) RetryPolicy(policy RetryPolicy) *fetchBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the Fetch request.
 func (b *fetchBuilder) Transport(tr http.RoundTripper) *fetchBuilder {
 	b.opts.Transport = tr
@@ -161,6 +168,8 @@ type fetchOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -282,6 +291,17 @@ func (o *fetchOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *fetchOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *fetchOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 func (o *fetchOpts) parseMessageActions(actions interface{}) map[string]PNHistoryMessageActionsTypeMap {
 	o.pubnub.Config.Log.Println(actions)
 	resp := make(map[string]PNHistoryMessageActionsTypeMap)
@@ -336,7 +356,7 @@ func (o *fetchOpts) parseMessageActions(actions interface{}) map[string]PNHistor
 }
 
 //{"status": 200, "error": false, "error_message": "", "channels": {"ch1":[{"message_type": "", "message": {"text": "hey"}, "timetoken": "15959610984115342", "meta": "", "uuid": "db9c5e39-7c95-40f5-8d71-125765b6f561"}]}}
-func (o *fetchOpts) fetchMessages(channels map[string]interface{}) map[string][]FetchResponseItem {
+func (o *fetchOpts) fetchMessages(channels map[string]interface{}, rawMessages map[string][]fetchRawMessage) map[string][]FetchResponseItem {
 	messages := make(map[string][]FetchResponseItem, len(channels))
 
 	for channel, histResponseSliceMap := range channels {
@@ -345,9 +365,13 @@ func (o *fetchOpts) fetchMessages(channels map[string]interface{}) map[string][]
 			items := make([]FetchResponseItem, len(histResponseMap))
 			count := 0
 
-			for _, val := range histResponseMap {
+			for i, val := range histResponseMap {
 				if histResponse, ok3 := val.(map[string]interface{}); ok3 {
-					msg, _ := parseCipherInterface(histResponse["message"], o.pubnub.Config)
+					var raw []byte
+					if i < len(rawMessages[channel]) {
+						raw = rawMessages[channel][i].Message
+					}
+					msg, _ := decodeMessage(raw, histResponse["message"], o.pubnub.Config)
 
 					histItem := FetchResponseItem{
 						Message:   msg,
@@ -405,6 +429,28 @@ func (o *fetchOpts) fetchMessages(channels map[string]interface{}) map[string][]
 	return messages
 }
 
+// fetchRawMessage is a fetched message left raw for a custom Serializer.
+type fetchRawMessage struct {
+	Message json.RawMessage `json:"message"`
+}
+
+// rawMessages returns the raw messages by channel when a custom Serializer is
+// set, they are decoded again with it.
+func (o *fetchOpts) rawMessages(jsonBytes []byte) map[string][]fetchRawMessage {
+	if o.pubnub.Config.Serializer == nil {
+		return nil
+	}
+
+	var value struct {
+		Channels map[string][]fetchRawMessage `json:"channels"`
+	}
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		o.pubnub.Config.Log.Println("raw messages:", err)
+		return nil
+	}
+	return value.Channels
+}
+
 func newFetchResponse(jsonBytes []byte, o *fetchOpts,
 	status StatusResponse) (*FetchResponse, StatusResponse, error) {
 
@@ -424,7 +470,7 @@ func newFetchResponse(jsonBytes []byte, o *fetchOpts,
 		o.pubnub.Config.Log.Println(result["channels"])
 		if channels, ok1 := result["channels"].(map[string]interface{}); ok1 {
 			if channels != nil {
-				resp.Messages = o.fetchMessages(channels)
+				resp.Messages = o.fetchMessages(channels, o.rawMessages(jsonBytes))
 			} else {
 				o.pubnub.Config.Log.Printf("type assertion to map failed %v\n", result)
 			}
@@ -1,5 +1,7 @@
 package pubnub
 
+import "io"
+
 // PNPublishMessage is the part of the message struct used in Publish File
 type PNPublishMessage struct {
 	Text string `json:"text"`
@@ -56,6 +58,44 @@ type PNFileMessageAndDetails struct {
 	PNFile    PNFileDetails    `json:"file"`
 }
 
+// PNFileTransferProgress is the progress of a file transfer reported to the
+// Progress callback of SendFile and DownloadFile. The bytes are the ones of
+// the file as stored, encrypted when the file is.
+type PNFileTransferProgress struct {
+	Phase FileTransferPhase
+	// Transferred is the number of bytes uploaded or downloaded.
+	Transferred int64
+	// Total is the size of the file, -1 when it's unknown.
+	Total int64
+}
+
+// progressReader reports the bytes read from Reader to report.
+type progressReader struct {
+	io.Reader
+	progress PNFileTransferProgress
+	report   func(PNFileTransferProgress)
+}
+
+func newProgressReader(reader io.Reader, phase FileTransferPhase, total int64,
+	report func(PNFileTransferProgress)) *progressReader {
+	r := &progressReader{
+		Reader:   reader,
+		progress: PNFileTransferProgress{Phase: phase, Total: total},
+		report:   report,
+	}
+	report(r.progress)
+	return r
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.progress.Transferred += int64(n)
+		r.report(r.progress)
+	}
+	return n, err
+}
+
 // ParseFileInfo is a function extract file info and add to the struct PNFileMessageAndDetails
 func ParseFileInfo(filesPayload map[string]interface{}) (PNFileDetails, PNPublishMessage) {
 	var data map[string]interface{}
@@ -68,6 +68,13 @@ func (b *deleteFileBuilder) QueryParam(queryParam map[string]string) *deleteFile
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *deleteFileBuilder) RetryPolicy(policy RetryPolicy) *deleteFileBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the deleteFile request.
 func (b *deleteFileBuilder) Transport(tr http.RoundTripper) *deleteFileBuilder {
 	b.opts.Transport = tr
@@ -94,6 +101,8 @@ type deleteFileOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -183,6 +192,17 @@ func (o *deleteFileOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *deleteFileOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *deleteFileOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNDeleteFileResponse is the File Upload API Response for Delete file operation
 type PNDeleteFileResponse struct {
 	status int `json:"status"`
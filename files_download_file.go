@@ -8,9 +8,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
-	"strconv"
+	"time"
 
-	"github.com/pubnub/go/v7/utils"
+	"github.com/pubnub/go/v7/pnerr"
 )
 
 var emptyDownloadFileResponse *PNDownloadFileResponse
@@ -69,6 +69,15 @@ func (b *downloadFileBuilder) Name(name string) *downloadFileBuilder {
 	return b
 }
 
+// Progress sets the callback reporting the bytes of the file read from
+// File, Total is the length of the response when the server sent it. The
+// callback is called by the goroutine reading File.
+func (b *downloadFileBuilder) Progress(progress func(PNFileTransferProgress)) *downloadFileBuilder {
+	b.opts.Progress = progress
+
+	return b
+}
+
 // QueryParam accepts a map, the keys and values of the map are passed as the query string parameters of the URL called by the API.
 func (b *downloadFileBuilder) QueryParam(queryParam map[string]string) *downloadFileBuilder {
 	b.opts.QueryParam = queryParam
@@ -76,59 +85,110 @@ func (b *downloadFileBuilder) QueryParam(queryParam map[string]string) *download
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *downloadFileBuilder) RetryPolicy(policy RetryPolicy) *downloadFileBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the downloadFile request.
 func (b *downloadFileBuilder) Transport(tr http.RoundTripper) *downloadFileBuilder {
 	b.opts.Transport = tr
 	return b
 }
 
+// Execute runs the downloadFile request, retried with the RetryPolicy.
 func (b *downloadFileBuilder) Execute() (*PNDownloadFileResponse, StatusResponse, error) {
-	u, _ := buildURL(b.opts)
+	policy := b.opts.retryPolicy()
+	for retries := 0; ; retries++ {
+		respDL, status, err := b.opts.download()
+		status.Retries = retries
+		if err == nil || !policy.shouldRetry(b.opts.operationType(), retries, err) {
+			return respDL, status, err
+		}
+
+		delay := policy.delay(retries, err)
+		b.opts.config().Log.Println(fmt.Sprintf("retry %d of %s in %s: %s", retries+1, b.opts.operationType(), delay, err))
+		if !waitRetry(b.opts.context(), delay) {
+			return respDL, status, err
+		}
+	}
+}
+
+// download runs an attempt of the download. The body of the response isn't
+// read, the File of the response reads it.
+func (o *downloadFileOpts) download() (*PNDownloadFileResponse, StatusResponse, error) {
 	stat := StatusResponse{
-		AffectedChannels: []string{b.opts.Channel},
-		AuthKey:          b.opts.config().AuthKey,
+		AffectedChannels: []string{o.Channel},
+		AuthKey:          o.config().AuthKey,
 		Category:         PNUnknownCategory,
 		Operation:        PNGetFileURLOperation,
 		StatusCode:       200,
-		TLSEnabled:       b.opts.config().Secure,
-		Origin:           b.opts.config().Origin,
-		UUID:             b.opts.config().UUID,
+		TLSEnabled:       o.config().Secure,
+		Origin:           o.config().Origin,
+		UUID:             o.config().UUID,
 	}
-	b.opts.pubnub.Config.Log.Printf("u.RequestURI(): %s", u.RequestURI())
-	resp, err := b.opts.client().Get(u.RequestURI())
-	if err != nil {
-		b.opts.pubnub.Config.Log.Printf("err %s", err)
+	if err := o.validate(); err != nil {
+		stat.StatusCode = 0
 		return nil, stat, err
 	}
-	if resp.StatusCode != 200 {
-		stat.StatusCode = resp.StatusCode
+
+	u, err := buildURL(o)
+	if err != nil {
+		stat.StatusCode = 0
 		return nil, stat, err
 	}
-	contentLenEnc, err := strconv.ParseInt(string(resp.Header.Get("Content-Length")), 10, 64)
+	stat.Origin = u.Host
+	o.pubnub.Config.Log.Printf("u.RequestURI(): %s", u.RequestURI())
+	req, err := newRequest("GET", u, nil, o.config().UseHTTP2)
 	if err != nil {
-		b.opts.pubnub.Config.Log.Printf("err in parsing content length %s", err)
+		stat.StatusCode = 0
 		return nil, stat, err
 	}
+	if ctx := o.context(); ctx != nil {
+		req = setRequestContext(req, ctx)
+	}
 
-	var respDL *PNDownloadFileResponse
-	if b.opts.CipherKey != "" {
-		r, w := io.Pipe()
-		utils.DecryptFile(b.opts.CipherKey, contentLenEnc, resp.Body, w)
-		respDL = &PNDownloadFileResponse{
-			File: r,
-		}
-
-	} else if b.opts.pubnub.Config.CipherKey != "" {
-		r, w := io.Pipe()
-		utils.DecryptFile(b.opts.pubnub.Config.CipherKey, contentLenEnc, resp.Body, w)
-		respDL = &PNDownloadFileResponse{
-			File: r,
+	resp, err := o.client().Do(req)
+	if err != nil {
+		o.pubnub.Config.Log.Printf("err %s", err)
+		e := pnerr.NewConnectionError("Failed to execute request", err)
+		stat.Category = categoryForError(e)
+		stat.StatusCode = 0
+		stat.Error = e
+		if stat.Category != PNCancelledCategory {
+			o.originManager().reportFailure(u.Host)
 		}
+		return nil, stat, e
+	}
+	o.originManager().reportSuccess(u.Host)
+	if resp.StatusCode != 200 {
+		e := pnerr.NewServerError(resp.StatusCode, resp.Body)
+		e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		resp.Body.Close()
+		stat.Category = categoryForError(e)
+		stat.StatusCode = resp.StatusCode
+		stat.Error = e
+		return nil, stat, e
+	}
 
-	} else {
-		respDL = &PNDownloadFileResponse{
-			File: resp.Body,
+	respDL := &PNDownloadFileResponse{
+		File: resp.Body,
+	}
+	var body io.Reader = resp.Body
+	if o.Progress != nil {
+		body = newProgressReader(body, PNFileTransferDownloading, resp.ContentLength, o.Progress)
+		respDL.File = downloadBody{Reader: body, Closer: resp.Body}
+	}
+	if module := fileCryptoModule(o.CipherKey, o.pubnub.Config); module != nil {
+		decrypted, err := module.DecryptStream(body)
+		if err != nil {
+			o.pubnub.Config.Log.Printf("err in decrypting the file %s", err)
+			resp.Body.Close()
+			return nil, stat, err
 		}
+		respDL.File = downloadBody{Reader: decrypted, Closer: resp.Body}
 	}
 	return respDL, stat, nil
 }
@@ -141,9 +201,12 @@ type downloadFileOpts struct {
 	ID         string
 	Name       string
 	QueryParam map[string]string
+	Progress   func(PNFileTransferProgress)
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -232,12 +295,32 @@ func (o *downloadFileOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
-// PNDownloadFileResponse is the File Upload API Response for Get Spaces
+func (o *downloadFileOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *downloadFileOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
+// PNDownloadFileResponse is the File Upload API Response for Get Spaces.
+// File is decrypted as it's read, its Read returns the decryption errors. It
+// implements io.Closer.
 type PNDownloadFileResponse struct {
 	status int       `json:"status"`
 	File   io.Reader `json:"data"`
 }
 
+// downloadBody is the body of a download read through the decryption or the
+// progress reporting, Close closes the response body.
+type downloadBody struct {
+	io.Reader
+	io.Closer
+}
+
 func newPNDownloadFileResponse(jsonBytes []byte, o *downloadFileOpts,
 	status StatusResponse) (*PNDownloadFileResponse, StatusResponse, error) {
 
@@ -0,0 +1,62 @@
+package pubnub
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadFileRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, "content", 500, 200))
+
+	resp, status, err := pn.DownloadFile().Channel("ch").ID("id").Name("name").
+		RetryPolicy(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}).Execute()
+	assert.Nil(err)
+	assert.Equal(2, attempts)
+	assert.Equal(1, status.Retries)
+	content, err := ioutil.ReadAll(resp.File)
+	assert.Nil(err)
+	assert.Equal("content", string(content))
+
+	// without retries the server error is returned
+	attempts = 0
+	_, status, err = pn.DownloadFile().Channel("ch").ID("id").Name("name").Execute()
+	var serverErr *pnerr.ServerError
+	assert.True(errors.As(err, &serverErr))
+	assert.Equal(1, attempts)
+	assert.Equal(500, status.StatusCode)
+	assert.Equal(PNServerErrorCategory, status.Category)
+
+	_, _, err = pn.DownloadFile().Channel("ch").Name("name").Execute()
+	assert.Contains(err.Error(), StrMissingFileID)
+	assert.Equal(1, attempts)
+}
+
+func TestDownloadFileContext(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+
+	ctx, cancel := contextWithCancel(backgroundContext)
+	cancel()
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})})
+
+	_, status, err := pn.DownloadFileWithContext(ctx).Channel("ch").ID("id").Name("name").
+		RetryPolicy(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Hour}).Execute()
+	assert.True(strings.Contains(err.Error(), "context canceled"))
+	assert.Equal(PNCancelledCategory, status.Category)
+}
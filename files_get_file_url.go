@@ -64,6 +64,13 @@ func (b *getFileURLBuilder) QueryParam(queryParam map[string]string) *getFileURL
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getFileURLBuilder) RetryPolicy(policy RetryPolicy) *getFileURLBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getFileURL request.
 func (b *getFileURLBuilder) Transport(tr http.RoundTripper) *getFileURLBuilder {
 	b.opts.Transport = tr
@@ -100,6 +107,8 @@ type getFileURLOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -188,6 +197,17 @@ func (o *getFileURLOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getFileURLOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getFileURLOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetFileURLResponse is the File Upload API Response for Get Spaces
 type PNGetFileURLResponse struct {
 	URL string `json:"location"`
@@ -72,6 +72,13 @@ func (b *listFilesBuilder) QueryParam(queryParam map[string]string) *listFilesBu
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *listFilesBuilder) RetryPolicy(policy RetryPolicy) *listFilesBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the listFiles request.
 func (b *listFilesBuilder) Transport(tr http.RoundTripper) *listFilesBuilder {
 	b.opts.Transport = tr
@@ -98,6 +105,8 @@ type listFilesOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -181,6 +190,17 @@ func (o *listFilesOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *listFilesOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *listFilesOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNListFilesResponse is the File Upload API Response for Get Spaces
 type PNListFilesResponse struct {
 	status int          `json:"status"`
@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
-	"os"
+	"time"
 
 	"github.com/pubnub/go/v7/pnerr"
 )
@@ -18,6 +19,11 @@ var emptySendFileResponse *PNSendFileResponse
 
 const sendFilePath = "/v1/files/%s/channels/%s/generate-upload-url"
 
+const (
+	fileMessagePublishRetryDelay    = 500 * time.Millisecond
+	fileMessagePublishMaxRetryDelay = 5 * time.Second
+)
+
 type sendFileBuilder struct {
 	opts *sendFileOpts
 }
@@ -88,12 +94,65 @@ func (b *sendFileBuilder) Message(message string) *sendFileBuilder {
 	return b
 }
 
-func (b *sendFileBuilder) File(f *os.File) *sendFileBuilder {
+// File sets the content of the file, it's streamed to the storage as it's
+// read and read again by the retries only when it's an io.Seeker.
+func (b *sendFileBuilder) File(f io.Reader) *sendFileBuilder {
 	b.opts.File = f
 
 	return b
 }
 
+// Size sets the size of the file. It's required by the readers other than
+// the regular files and the in-memory readers.
+func (b *sendFileBuilder) Size(size int64) *sendFileBuilder {
+	b.opts.Size = size
+	b.opts.setSize = true
+
+	return b
+}
+
+// ContentType sets the content type of the file, it's detected from its
+// first 512 bytes when not set.
+func (b *sendFileBuilder) ContentType(contentType string) *sendFileBuilder {
+	b.opts.ContentType = contentType
+
+	return b
+}
+
+// Progress sets the callback reporting the phase of the transfer and the
+// bytes of the file uploaded. Transferred and Total keep the values of the
+// upload in the publishing phase. The callback is called by the goroutine of
+// Execute or by the one writing the upload.
+func (b *sendFileBuilder) Progress(progress func(PNFileTransferProgress)) *sendFileBuilder {
+	b.opts.Progress = progress
+
+	return b
+}
+
+// Checkpoint sets the handler called with the state of the workflow after
+// each step, the state is kept to resume the workflow if the process stops.
+func (b *sendFileBuilder) Checkpoint(handler func(PNSendFileState)) *sendFileBuilder {
+	b.opts.Checkpoint = handler
+
+	return b
+}
+
+// Resume sets the parameters and the step of the workflow from state. The
+// file message of an uploaded file is published without the file, the
+// workflow is started again with File when the upload wasn't done, under a
+// new file ID.
+func (b *sendFileBuilder) Resume(state PNSendFileState) *sendFileBuilder {
+	b.opts.Channel = state.Channel
+	b.opts.Name = state.Name
+	b.opts.Message = state.Message
+	b.opts.Meta = state.Meta
+	b.opts.TTL = state.TTL
+	b.opts.ShouldStore = state.ShouldStore
+	b.opts.resumed = state
+
+	return b
+}
+
 // QueryParam accepts a map, the keys and values of the map are passed as the query string parameters of the URL called by the API.
 func (b *sendFileBuilder) QueryParam(queryParam map[string]string) *sendFileBuilder {
 	b.opts.QueryParam = queryParam
@@ -101,6 +160,13 @@ func (b *sendFileBuilder) QueryParam(queryParam map[string]string) *sendFileBuil
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *sendFileBuilder) RetryPolicy(policy RetryPolicy) *sendFileBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the sendFile request.
 func (b *sendFileBuilder) Transport(tr http.RoundTripper) *sendFileBuilder {
 	b.opts.Transport = tr
@@ -109,6 +175,11 @@ func (b *sendFileBuilder) Transport(tr http.RoundTripper) *sendFileBuilder {
 
 // Execute runs the sendFile request.
 func (b *sendFileBuilder) Execute() (*PNSendFileResponse, StatusResponse, error) {
+	if done, resp, status, err := b.opts.resume(); done {
+		return resp, status, err
+	}
+
+	b.opts.reportProgress(PNFileTransferProgress{Phase: PNFileTransferRequestingURL, Total: -1})
 	rawJSON, status, err := executeRequest(b.opts)
 	if err != nil {
 		return emptySendFileResponse, status, err
@@ -123,16 +194,107 @@ type sendFileOpts struct {
 	Channel     string
 	Name        string
 	Message     string
-	File        *os.File
+	File        io.Reader
+	Size        int64
+	ContentType string
 	CipherKey   string
 	TTL         int
 	Meta        interface{}
 	ShouldStore bool
 	QueryParam  map[string]string
+	Progress    func(PNFileTransferProgress)
+	Checkpoint  func(PNSendFileState)
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
+
+	// nil hacks
+	setSize bool
+
+	// progress is the last progress reported.
+	progress PNFileTransferProgress
+	// resumed is the state set by Resume.
+	resumed PNSendFileState
+}
+
+// resume runs the steps left of the workflow set by Resume, done is false
+// when the workflow is run from the start. The file of a workflow resumed at
+// PNSendFileStepUpload is looked up with ListFiles as its upload may be done
+// without its checkpoint, it's uploaded again under a new ID otherwise: the
+// upload URL of the old ID isn't kept and no file was stored under it.
+func (o *sendFileOpts) resume() (done bool, resp *PNSendFileResponse, status StatusResponse, err error) {
+	switch o.resumed.Step {
+	case PNSendFileStepDone:
+		resp = &PNSendFileResponse{
+			Timestamp: o.resumed.Timestamp,
+			Data:      PNFileData{ID: o.resumed.ID},
+		}
+		return true, resp, o.status(PNAcknowledgmentCategory, 200, nil), nil
+	case PNSendFileStepUpload, PNSendFileStepPublish:
+		if o.resumed.ID == "" {
+			err = newValidationError(o, StrMissingFileID)
+			return true, emptySendFileResponse, o.status(PNBadRequestCategory, 0, err), err
+		}
+	default:
+		return false, nil, status, nil
+	}
+
+	if o.resumed.Step == PNSendFileStepUpload {
+		ids, status, err := o.pubnub.listFileIDs(o.context(), o.Channel)
+		if err != nil {
+			return true, emptySendFileResponse, status, err
+		}
+		if !ids[o.resumed.ID] {
+			return false, nil, status, nil
+		}
+		o.checkpoint(PNSendFileStepPublish, o.resumed.ID, 0)
+	}
+
+	resp, status, err = o.publishFileMessage(o.resumed.ID)
+	return true, resp, status, err
+}
+
+// status returns the status of a SendFile not sending requests.
+func (o *sendFileOpts) status(category StatusCategory, statusCode int, err error) StatusResponse {
+	status := createStatus(category, "", ResponseInfo{
+		Operation:  PNSendFileOperation,
+		StatusCode: statusCode,
+		TLSEnabled: o.pubnub.Config.Secure,
+		Origin:     o.pubnub.Config.Origin,
+		UUID:       o.pubnub.Config.UUID,
+		AuthKey:    o.pubnub.Config.AuthKey,
+	}, err)
+	status.AffectedChannels = []string{o.Channel}
+	return status
+}
+
+// checkpoint passes the state of the workflow at step to Checkpoint.
+func (o *sendFileOpts) checkpoint(step SendFileStep, id string, timestamp int64) {
+	if o.Checkpoint == nil {
+		return
+	}
+	o.Checkpoint(PNSendFileState{
+		Step:        step,
+		ID:          id,
+		Name:        o.Name,
+		Channel:     o.Channel,
+		Message:     o.Message,
+		Meta:        o.Meta,
+		TTL:         o.TTL,
+		ShouldStore: o.ShouldStore,
+		Timestamp:   timestamp,
+	})
+}
+
+func (o *sendFileOpts) reportProgress(progress PNFileTransferProgress) {
+	if o.Progress == nil {
+		return
+	}
+	o.progress = progress
+	o.Progress(progress)
 }
 
 func (o *sendFileOpts) config() Config {
@@ -159,7 +321,11 @@ func (o *sendFileOpts) validate() error {
 	if o.Name == "" {
 		return newValidationError(o, StrMissingFileName)
 	}
-	return nil
+
+	if o.File == nil {
+		return newValidationError(o, StrMissingFile)
+	}
+	return validateFileSize(o, o.File, o.setSize, o.CipherKey, o.pubnub.Config)
 }
 
 func (o *sendFileOpts) buildPath() (string, error) {
@@ -230,6 +396,17 @@ func (o *sendFileOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *sendFileOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *sendFileOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSendFileResponseForS3 is the File Upload API Response for SendFile.
 type PNSendFileResponseForS3 struct {
 	status            int                 `json:"status"`
@@ -244,7 +421,6 @@ type PNSendFileResponse struct {
 	Data      PNFileData `json:"data"`
 }
 
-// TODO Add retry on publish failure
 func newPNSendFileResponse(jsonBytes []byte, o *sendFileOpts,
 	status StatusResponse) (*PNSendFileResponse, StatusResponse, error) {
 
@@ -256,24 +432,60 @@ func newPNSendFileResponse(jsonBytes []byte, o *sendFileOpts,
 			ioutil.NopCloser(bytes.NewBufferString(string(jsonBytes))), err)
 		return emptySendFileResponse, status, e
 	}
+	o.checkpoint(PNSendFileStepUpload, respForS3.Data.ID, 0)
+
 	var s *sendFileToS3Builder
 	if o.context() != nil {
 		s = newSendFileToS3BuilderWithContext(o.pubnub, o.context())
 	} else {
 		s = newSendFileToS3Builder(o.pubnub)
 	}
-	_, s3ResponseStatus, errS3Response := s.File(o.File).CipherKey(o.CipherKey).FileUploadRequestData(respForS3.FileUploadRequest).Execute()
+	s.opts.RetryPolicy = o.RetryPolicy
+	s.opts.Size = o.Size
+	s.opts.setSize = o.setSize
+	if o.Progress != nil {
+		s.opts.Progress = o.reportProgress
+	}
+	_, s3ResponseStatus, errS3Response := s.File(o.File).Name(o.Name).ContentType(o.ContentType).CipherKey(o.CipherKey).FileUploadRequestData(respForS3.FileUploadRequest).Execute()
 	if s3ResponseStatus.StatusCode != 204 {
 		o.pubnub.Config.Log.Printf("s3ResponseStatus: %d", s3ResponseStatus.StatusCode)
 		return emptySendFileResponse, s3ResponseStatus, errS3Response
 	}
 
+	o.checkpoint(PNSendFileStepPublish, respForS3.Data.ID, 0)
+
+	resp, pubFileResponseStatus, err := o.publishFileMessage(respForS3.Data.ID)
+	if err != nil {
+		return resp, pubFileResponseStatus, err
+	}
+
+	return resp, status, nil
+}
+
+// publishRetryDelay returns the delay before the retry following retries
+// failed publishes of the file message, with the backoff of the RetryPolicy
+// or fileMessagePublishRetryDelay doubled when it has no delay.
+func (o *sendFileOpts) publishRetryDelay(retries int, err error) time.Duration {
+	policy := o.retryPolicy()
+	if policy.InitialDelay <= 0 {
+		policy = RetryPolicy{
+			InitialDelay: fileMessagePublishRetryDelay,
+			MaxDelay:     fileMessagePublishMaxRetryDelay,
+		}
+	}
+	return policy.delay(retries, err)
+}
+
+// publishFileMessage publishes the file message of the uploaded file id,
+// tried FileMessagePublishRetryLimit times with a backoff until the context
+// of the request is done.
+func (o *sendFileOpts) publishFileMessage(id string) (*PNSendFileResponse, StatusResponse, error) {
 	m := &PNPublishMessage{
 		Text: o.Message,
 	}
 
 	file := &PNFileInfoForPublish{
-		ID:   respForS3.Data.ID,
+		ID:   id,
 		Name: o.Name,
 	}
 
@@ -282,28 +494,45 @@ func newPNSendFileResponse(jsonBytes []byte, o *sendFileOpts,
 		PNMessage: m,
 	}
 
+	o.reportProgress(PNFileTransferProgress{
+		Phase:       PNFileTransferPublishing,
+		Transferred: o.progress.Transferred,
+		Total:       o.progress.Total,
+	})
+
 	sent := false
 	tryCount := 0
 	var timestamp int64
+	var status StatusResponse
 	maxCount := o.config().FileMessagePublishRetryLimit
 	for !sent && tryCount < maxCount {
 		tryCount++
-		pubFileMessageResponse, pubFileResponseStatus, errPubFileResponse := o.pubnub.PublishFileMessage().TTL(o.TTL).Meta(o.Meta).ShouldStore(o.ShouldStore).Channel(o.Channel).Message(message).Execute()
+		publish := o.pubnub.PublishFileMessage()
+		if o.context() != nil {
+			publish = o.pubnub.PublishFileMessageWithContext(o.context())
+		}
+		pubFileMessageResponse, pubFileResponseStatus, errPubFileResponse := publish.TTL(o.TTL).Meta(o.Meta).ShouldStore(o.ShouldStore).Channel(o.Channel).Message(message).Execute()
 		if errPubFileResponse != nil {
-			if tryCount >= maxCount {
+			if tryCount >= maxCount || !waitRetry(o.context(), o.publishRetryDelay(tryCount-1, errPubFileResponse)) {
 				pubFileResponseStatus.AdditionalData = file
 				return emptySendFileResponse, pubFileResponseStatus, errPubFileResponse
 			}
 			continue
 		} else {
 			timestamp = pubFileMessageResponse.Timestamp
+			status = pubFileResponseStatus
 			sent = true
 			break
 		}
 	}
+
+	if sent {
+		o.checkpoint(PNSendFileStepDone, id, timestamp)
+	}
+
 	resp := &PNSendFileResponse{}
 	d := PNFileData{}
-	d.ID = respForS3.Data.ID
+	d.ID = id
 	resp.Data = d
 	resp.Timestamp = timestamp
 
@@ -0,0 +1,107 @@
+package pubnub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// PNSendFileState is the state of a SendFile workflow, passed to the
+// Checkpoint handler of SendFile after each step. Its Token is kept to finish
+// the workflow with SendFile().Resume if the process stops before it's done.
+type PNSendFileState struct {
+	// Step is the next step of the workflow.
+	Step SendFileStep `json:"step"`
+	// ID is the ID of the file, known from PNSendFileStepUpload.
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Channel     string      `json:"channel"`
+	Message     string      `json:"message,omitempty"`
+	Meta        interface{} `json:"meta,omitempty"`
+	TTL         int         `json:"ttl,omitempty"`
+	ShouldStore bool        `json:"store,omitempty"`
+	// Timestamp is the timetoken of the file message once it's published.
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// Token returns the state serialized in a URL safe string.
+func (s PNSendFileState) Token() (string, error) {
+	jsonBytes, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(jsonBytes), nil
+}
+
+// ParseSendFileState returns the state serialized by PNSendFileState.Token.
+func ParseSendFileState(token string) (PNSendFileState, error) {
+	var state PNSendFileState
+	jsonBytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(jsonBytes, &state)
+	return state, err
+}
+
+// UnpublishedFiles returns the states of states whose file is uploaded but
+// whose file message isn't published, with their Step set to
+// PNSendFileStepPublish. The files are looked up with ListFiles, a file
+// listed was uploaded even if the upload step wasn't reported.
+func (pn *PubNub) UnpublishedFiles(states []PNSendFileState) ([]PNSendFileState, StatusResponse, error) {
+	return pn.unpublishedFiles(nil, states)
+}
+
+// UnpublishedFilesWithContext returns the states of states whose file is
+// uploaded but whose file message isn't published, see UnpublishedFiles.
+func (pn *PubNub) UnpublishedFilesWithContext(ctx Context, states []PNSendFileState) ([]PNSendFileState, StatusResponse, error) {
+	return pn.unpublishedFiles(ctx, states)
+}
+
+func (pn *PubNub) unpublishedFiles(ctx Context, states []PNSendFileState) ([]PNSendFileState, StatusResponse, error) {
+	var status StatusResponse
+	uploaded := map[string]map[string]bool{}
+	var unpublished []PNSendFileState
+	for _, state := range states {
+		if state.ID == "" || state.Step < PNSendFileStepUpload || state.Step >= PNSendFileStepDone {
+			continue
+		}
+
+		ids, ok := uploaded[state.Channel]
+		if !ok {
+			var err error
+			ids, status, err = pn.listFileIDs(ctx, state.Channel)
+			if err != nil {
+				return nil, status, err
+			}
+			uploaded[state.Channel] = ids
+		}
+		if ids[state.ID] {
+			state.Step = PNSendFileStepPublish
+			unpublished = append(unpublished, state)
+		}
+	}
+	return unpublished, status, nil
+}
+
+// listFileIDs returns the IDs of all the files of channel.
+func (pn *PubNub) listFileIDs(ctx Context, channel string) (map[string]bool, StatusResponse, error) {
+	ids := map[string]bool{}
+	next := ""
+	for {
+		builder := pn.ListFiles()
+		if ctx != nil {
+			builder = pn.ListFilesWithContext(ctx)
+		}
+		resp, status, err := builder.Channel(channel).Next(next).Execute()
+		if err != nil {
+			return nil, status, err
+		}
+		for _, file := range resp.Data {
+			ids[file.ID] = true
+		}
+		if resp.Next == "" || len(resp.Data) == 0 {
+			return ids, status, nil
+		}
+		next = resp.Next
+	}
+}
@@ -0,0 +1,171 @@
+package pubnub
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendFileStateToken(t *testing.T) {
+	assert := assert.New(t)
+	state := PNSendFileState{
+		Step:        PNSendFileStepPublish,
+		ID:          "fileID",
+		Name:        "name",
+		Channel:     "ch",
+		Message:     "message",
+		Meta:        map[string]interface{}{"m": "v"},
+		TTL:         10,
+		ShouldStore: true,
+	}
+
+	token, err := state.Token()
+	assert.Nil(err)
+	assert.NotContains(token, "/")
+	parsed, err := ParseSendFileState(token)
+	assert.Nil(err)
+	assert.Equal(state, parsed)
+
+	_, err = ParseSendFileState("not a token")
+	assert.NotNil(err)
+}
+
+// newSendFileClient returns a client answering the requests of SendFile, the
+// file messages fail with failedPublishes first. It counts the requests by
+// kind in requests.
+func newSendFileClient(requests map[string]int, failedPublishes int) *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		code, body := 200, ""
+		switch {
+		case strings.Contains(req.URL.String(), "generate-upload-url"):
+			requests["url"]++
+			body = `{"status":200,"data":{"id":"fileID","name":"name"},"file_upload_request":{"url":"https://storage.example.com/","method":"POST","form_fields":[]}}`
+		case strings.Contains(req.URL.String(), "publish-file"):
+			requests["publish"]++
+			body = `[1,"Sent","16000000000000000"]`
+			if requests["publish"] <= failedPublishes {
+				code, body = 500, `{"error": true}`
+			}
+		case strings.Contains(req.URL.String(), "/files"):
+			requests["list"]++
+			body = `{"status":200,"data":[{"id":"a","name":"a"}],"next":"page2","count":1}`
+			if strings.Contains(req.URL.String(), "next=page2") {
+				body = `{"status":200,"data":[{"id":"b","name":"b"}],"count":1}`
+			}
+		default:
+			requests["upload"]++
+			code = 204
+			ioutil.ReadAll(req.Body)
+		}
+		return &http.Response{
+			StatusCode: code,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})}
+}
+
+func TestSendFileResume(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.FileMessagePublishRetryLimit = 2
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	requests := map[string]int{}
+	pn.SetClient(newSendFileClient(requests, 2))
+
+	var states []PNSendFileState
+	checkpoint := func(state PNSendFileState) {
+		states = append(states, state)
+	}
+	_, _, err := pn.SendFile().Channel("ch").Name("name").Message("message").TTL(10).
+		File(strings.NewReader("content")).Checkpoint(checkpoint).Execute()
+	assert.NotNil(err)
+	if !assert.Len(states, 2) {
+		return
+	}
+	assert.Equal(PNSendFileStepUpload, states[0].Step)
+	assert.Equal(PNSendFileState{
+		Step:    PNSendFileStepPublish,
+		ID:      "fileID",
+		Name:    "name",
+		Channel: "ch",
+		Message: "message",
+		TTL:     10,
+	}, states[1])
+
+	// the file message is published without uploading the file again
+	token, err := states[1].Token()
+	assert.Nil(err)
+	state, err := ParseSendFileState(token)
+	assert.Nil(err)
+	resp, _, err := pn.SendFile().Resume(state).Checkpoint(checkpoint).Execute()
+	assert.Nil(err)
+	assert.Equal("fileID", resp.Data.ID)
+	assert.Equal(int64(16000000000000000), resp.Timestamp)
+	assert.Equal(map[string]int{"url": 1, "upload": 1, "publish": 3}, requests)
+	if assert.Len(states, 3) {
+		assert.Equal(PNSendFileStepDone, states[2].Step)
+		assert.Equal(int64(16000000000000000), states[2].Timestamp)
+	}
+
+	// a finished workflow isn't run again
+	resp, status, err := pn.SendFile().Resume(states[2]).Execute()
+	assert.Nil(err)
+	assert.Equal("fileID", resp.Data.ID)
+	assert.Equal(3, requests["publish"])
+	assert.Equal(PNSendFileOperation, status.Operation)
+	assert.Equal(PNAcknowledgmentCategory, status.Category)
+	assert.Equal(200, status.StatusCode)
+	assert.Equal([]string{"ch"}, status.AffectedChannels)
+
+	// a file listed was uploaded, its upload isn't checkpointed
+	state.Step = PNSendFileStepUpload
+	state.ID = "b"
+	resp, _, err = pn.SendFile().Resume(state).Execute()
+	assert.Nil(err)
+	assert.Equal("b", resp.Data.ID)
+	assert.Equal(map[string]int{"url": 1, "upload": 1, "publish": 4, "list": 2}, requests)
+
+	// the upload is started again with the file when it's not listed
+	state.ID = "fileID"
+	_, _, err = pn.SendFile().Resume(state).Execute()
+	assert.Contains(err.Error(), StrMissingFile)
+	_, _, err = pn.SendFile().Resume(state).File(strings.NewReader("content")).Execute()
+	assert.Nil(err)
+	assert.Equal(2, requests["upload"])
+
+	state.ID = ""
+	_, status, err = pn.SendFile().Resume(state).Execute()
+	assert.Contains(err.Error(), StrMissingFileID)
+	assert.Equal(PNSendFileOperation, status.Operation)
+}
+
+func TestUnpublishedFiles(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+
+	requests := map[string]int{}
+	pn.SetClient(newSendFileClient(requests, 0))
+
+	states := []PNSendFileState{
+		{Step: PNSendFileStepUpload, ID: "a", Channel: "ch"},
+		{Step: PNSendFileStepPublish, ID: "b", Channel: "ch"},
+		{Step: PNSendFileStepUpload, ID: "c", Channel: "ch"},
+		{Step: PNSendFileStepDone, ID: "a", Channel: "ch"},
+		{Step: PNSendFileStepRequestURL, Channel: "ch"},
+	}
+	unpublished, _, err := pn.UnpublishedFiles(states)
+	assert.Nil(err)
+	assert.Equal([]PNSendFileState{
+		{Step: PNSendFileStepPublish, ID: "a", Channel: "ch"},
+		{Step: PNSendFileStepPublish, ID: "b", Channel: "ch"},
+	}, unpublished)
+	// the files of a channel are listed once
+	assert.Equal(2, requests["list"])
+}
@@ -2,8 +2,13 @@ package pubnub
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pubnub/go/v7/crypto"
 	h "github.com/pubnub/go/v7/tests/helpers"
 	"github.com/stretchr/testify/assert"
 )
@@ -68,3 +73,80 @@ func TestSendFileResponseValueError(t *testing.T) {
 	_, _, err := newPNSendFileResponse(jsonBytes, opts, StatusResponse{})
 	assert.Equal("pubnub/parsing: Error unmarshalling response: {s}", err.Error())
 }
+
+func TestSendFileProgress(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(newAesGcmConfig(t))
+	defer pn.Destroy()
+
+	content := strings.Repeat("file content ", 20000)
+	var uploaded []byte
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		code, body := 200, ""
+		switch {
+		case strings.Contains(req.URL.String(), "generate-upload-url"):
+			body = `{"status":200,"data":{"id":"fileID","name":"name"},"file_upload_request":{"url":"https://storage.example.com/","method":"POST","form_fields":[]}}`
+		case strings.Contains(req.URL.String(), "publish-file"):
+			body = `[1,"Sent","16000000000000000"]`
+		default:
+			code = 204
+			_, uploaded = readMultipartBody(t, req.Body, req.Header.Get("Content-Type"))
+		}
+		return &http.Response{
+			StatusCode: code,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})})
+
+	var progress []PNFileTransferProgress
+	resp, _, err := pn.SendFile().Channel("ch").Name("name").File(strings.NewReader(content)).
+		Progress(func(p PNFileTransferProgress) {
+			progress = append(progress, p)
+		}).Execute()
+	assert.Nil(err)
+	assert.Equal("fileID", resp.Data.ID)
+
+	// the bytes uploaded are the encrypted ones
+	size, ok := crypto.EncryptedStreamSize(pn.Config.CryptoModule, int64(len(content)))
+	assert.True(ok)
+	assert.Equal(size, int64(len(uploaded)))
+
+	if !assert.True(len(progress) > 3) {
+		return
+	}
+	assert.Equal(PNFileTransferProgress{Phase: PNFileTransferRequestingURL, Total: -1}, progress[0])
+	assert.Equal(PNFileTransferProgress{Phase: PNFileTransferUploading, Total: size}, progress[1])
+	for i := 2; i < len(progress)-1; i++ {
+		assert.Equal(PNFileTransferUploading, progress[i].Phase)
+		assert.True(progress[i].Transferred > progress[i-1].Transferred)
+	}
+	assert.Equal(PNFileTransferProgress{Phase: PNFileTransferPublishing, Transferred: size, Total: size}, progress[len(progress)-1])
+}
+
+func TestSendFilePublishCancelled(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.FileMessagePublishRetryLimit = 5
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	ctx, cancel := contextWithCancel(backgroundContext)
+	defer cancel()
+	requests := map[string]int{}
+	transport := newSendFileClient(requests, 5).Transport
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.String(), "publish-file") {
+			// the SendFile is cancelled while the file message fails
+			cancel()
+		}
+		return transport.RoundTrip(req)
+	})})
+
+	start := time.Now()
+	_, _, err := pn.SendFileWithContext(ctx).Channel("ch").Name("name").
+		File(strings.NewReader("content")).Execute()
+	assert.NotNil(err)
+	assert.Equal(1, requests["publish"])
+	assert.True(time.Since(start) < fileMessagePublishRetryDelay)
+}
@@ -1,19 +1,33 @@
 package pubnub
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 
+	"github.com/pubnub/go/v7/crypto"
 	"github.com/pubnub/go/v7/pnerr"
-	"github.com/pubnub/go/v7/utils"
 )
 
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// byteCounter is a writer counting the bytes written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
 var emptySendFileToS3Response *PNSendFileToS3Response
 
 type sendFileToS3Builder struct {
@@ -54,12 +68,45 @@ func (b *sendFileToS3Builder) FileUploadRequestData(fileUploadRequestData PNFile
 	return b
 }
 
-func (b *sendFileToS3Builder) File(f *os.File) *sendFileToS3Builder {
+// File sets the content of the file, it's read as it's uploaded.
+func (b *sendFileToS3Builder) File(f io.Reader) *sendFileToS3Builder {
 	b.opts.File = f
 
 	return b
 }
 
+// Size sets the size of the file. It's required by the readers other than
+// the regular files and the in-memory readers.
+func (b *sendFileToS3Builder) Size(size int64) *sendFileToS3Builder {
+	b.opts.Size = size
+	b.opts.setSize = true
+
+	return b
+}
+
+// ContentType sets the content type of the file, it's detected from its
+// first 512 bytes when not set.
+func (b *sendFileToS3Builder) ContentType(contentType string) *sendFileToS3Builder {
+	b.opts.ContentType = contentType
+
+	return b
+}
+
+// Progress sets the callback reporting the bytes of the file uploaded.
+func (b *sendFileToS3Builder) Progress(progress func(PNFileTransferProgress)) *sendFileToS3Builder {
+	b.opts.Progress = progress
+
+	return b
+}
+
+// Name sets the name of the file in the multipart body, it defaults to the
+// base name of the file.
+func (b *sendFileToS3Builder) Name(name string) *sendFileToS3Builder {
+	b.opts.Name = name
+
+	return b
+}
+
 // QueryParam accepts a map, the keys and values of the map are passed as the query string parameters of the URL called by the API.
 func (b *sendFileToS3Builder) QueryParam(queryParam map[string]string) *sendFileToS3Builder {
 	b.opts.QueryParam = queryParam
@@ -67,6 +114,13 @@ func (b *sendFileToS3Builder) QueryParam(queryParam map[string]string) *sendFile
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *sendFileToS3Builder) RetryPolicy(policy RetryPolicy) *sendFileToS3Builder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the sendFileToS3 request.
 func (b *sendFileToS3Builder) Transport(tr http.RoundTripper) *sendFileToS3Builder {
 	b.opts.Transport = tr
@@ -86,13 +140,27 @@ func (b *sendFileToS3Builder) Execute() (*PNSendFileToS3Response, StatusResponse
 type sendFileToS3Opts struct {
 	pubnub *PubNub
 
-	File                  *os.File
+	File                  io.Reader
+	Size                  int64
+	ContentType           string
+	Name                  string
 	FileUploadRequestData PNFileUploadRequest
 	QueryParam            map[string]string
 	CipherKey             string
+	Progress              func(PNFileTransferProgress)
 	Transport             http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
+
+	// nil hacks
+	setSize bool
+
+	// start is the offset of File when it was first read, the retries
+	// rewind it there.
+	start    int64
+	startSet bool
 }
 
 func (o *sendFileToS3Opts) config() Config {
@@ -112,7 +180,11 @@ func (o *sendFileToS3Opts) validate() error {
 		return newValidationError(o, StrMissingSubKey)
 	}
 
-	return nil
+	if o.File == nil {
+		return newValidationError(o, StrMissingFile)
+	}
+
+	return validateFileSize(o, o.File, o.setSize, o.CipherKey, o.pubnub.Config)
 }
 
 func (o *sendFileToS3Opts) buildPath() (string, error) {
@@ -132,56 +204,156 @@ func (o *sendFileToS3Opts) buildBody() ([]byte, error) {
 }
 
 func (o *sendFileToS3Opts) buildBodyMultipartFileUpload() (bytes.Buffer, *multipart.Writer, int64, error) {
+	return bytes.Buffer{}, nil, 0, errors.New("Not required")
+}
 
-	fileInfo, _ := o.File.Stat()
-	s := fileInfo.Size()
-	buffer := make([]byte, 512)
-	_, err := o.File.Read(buffer)
+// streamBodyMultipartFileUpload returns the multipart body, written by a
+// goroutine to a pipe as the request reads it, and its length required by
+// the storage.
+func (o *sendFileToS3Opts) streamBodyMultipartFileUpload() (io.ReadCloser, string, int64, error) {
+	file, size, err := o.fileReader()
 	if err != nil {
-		return bytes.Buffer{}, nil, s, err
+		o.pubnub.Config.Log.Printf("ERROR: file read error: %s\n", err.Error())
+		return nil, "", 0, err
 	}
-	o.File.Seek(0, 0)
-	contentType := http.DetectContentType(buffer)
 
-	var fileBody bytes.Buffer
-	writer := multipart.NewWriter(&fileBody)
+	contentType := o.ContentType
+	if contentType == "" {
+		buffered := bufio.NewReaderSize(file, sniffLen)
+		// the errors are returned by the upload
+		head, _ := buffered.Peek(sniffLen)
+		contentType = http.DetectContentType(head)
+		file = buffered
+	}
 
-	for _, v := range o.FileUploadRequestData.FormFields {
-		o.pubnub.Config.Log.Printf("FormFields: Key: %s Value: %s\n", v.Key, v.Value)
-		if v.Key == "Content-Type" {
-			v.Value = contentType
+	if size < 0 {
+		return nil, "", 0, newValidationError(o, StrMissingFileSize)
+	}
+
+	if module := fileCryptoModule(o.CipherKey, o.pubnub.Config); module != nil {
+		encryptedSize, ok := crypto.EncryptedStreamSize(module, size)
+		if !ok {
+			return nil, "", 0, newValidationError(o, StrUnknownEncryptedFileSize)
+		}
+		size = encryptedSize
+		encrypted, errEncrypt := module.EncryptStream(file)
+		if errEncrypt != nil {
+			o.pubnub.Config.Log.Printf("ERROR: file encryption error: %s\n", errEncrypt.Error())
+			return nil, "", 0, errEncrypt
 		}
-		_ = writer.WriteField(v.Key, v.Value)
+		file = encrypted
 	}
 
-	filePart, errFilePart := writer.CreateFormFile("file", fileInfo.Name())
+	if o.Progress != nil {
+		file = newProgressReader(file, PNFileTransferUploading, size, o.Progress)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(o.writeMultipartBody(writer, contentType, file))
+	}()
+
+	// the length of the body without the file
+	var envelope byteCounter
+	dryRun := multipart.NewWriter(&envelope)
+	if err := dryRun.SetBoundary(writer.Boundary()); err != nil {
+		pr.Close()
+		return nil, "", 0, err
+	}
+	if err := o.writeMultipartBody(dryRun, contentType, &bytes.Reader{}); err != nil {
+		pr.Close()
+		return nil, "", 0, err
+	}
+
+	return pr, writer.FormDataContentType(), int64(envelope) + size, nil
+}
+
+// validateFileSize checks the length of the upload of file is known before
+// it's read, the storage requires it and the file isn't buffered.
+func validateFileSize(o endpointOpts, file io.Reader, setSize bool, cipherKey string, config *Config) error {
+	if !setSize && !hasKnownSize(file) {
+		return newValidationError(o, StrMissingFileSize)
+	}
+	if module := fileCryptoModule(cipherKey, config); module != nil {
+		if _, ok := crypto.EncryptedStreamSize(module, 0); !ok {
+			return newValidationError(o, StrUnknownEncryptedFileSize)
+		}
+	}
+	return nil
+}
 
-	if errFilePart != nil {
-		o.pubnub.Config.Log.Printf("ERROR: writer CreateFormFile: %s\n", errFilePart.Error())
-		return bytes.Buffer{}, writer, s, errFilePart
+// hasKnownSize reports whether the size of file is known without Size, for
+// the regular files and the in-memory readers.
+func hasKnownSize(file io.Reader) bool {
+	switch f := file.(type) {
+	case *os.File:
+		info, err := f.Stat()
+		return err == nil && info.Mode().IsRegular()
+	case interface{ Len() int }:
+		return true
 	}
+	return false
+}
 
-	if o.CipherKey != "" {
-		utils.EncryptFile(o.CipherKey, []byte{}, filePart, o.File)
-	} else if o.pubnub.Config.CipherKey != "" {
-		utils.EncryptFile(o.pubnub.Config.CipherKey, []byte{}, filePart, o.File)
-	} else {
-		_, errIOCopy := io.Copy(filePart, o.File)
+// fileReader returns File, rewound to its start on the retries, and its size
+// or -1 when it's unknown.
+func (o *sendFileToS3Opts) fileReader() (io.Reader, int64, error) {
+	if seeker, ok := o.File.(io.Seeker); ok {
+		if !o.startSet {
+			start, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, 0, err
+			}
+			o.start, o.startSet = start, true
+		} else if _, err := seeker.Seek(o.start, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+	}
 
-		if errIOCopy != nil {
-			o.pubnub.Config.Log.Printf("ERROR: io Copy error: %s\n", errIOCopy.Error())
-			return bytes.Buffer{}, writer, s, errIOCopy
+	if o.setSize {
+		return o.File, o.Size, nil
+	}
+	switch f := o.File.(type) {
+	case *os.File:
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			return f, info.Size() - o.start, nil
 		}
+	case interface{ Len() int }:
+		return o.File, int64(f.Len()), nil
 	}
+	return o.File, -1, nil
+}
 
-	errWriterClose := writer.Close()
-	if errWriterClose != nil {
-		o.pubnub.Config.Log.Printf("ERROR: Writer close: %s\n", errWriterClose.Error())
-		return bytes.Buffer{}, writer, s, errWriterClose
+// writeMultipartBody writes the form fields and the file to w and closes it.
+func (o *sendFileToS3Opts) writeMultipartBody(w *multipart.Writer, contentType string, file io.Reader) error {
+	for _, v := range o.FileUploadRequestData.FormFields {
+		if v.Key == "Content-Type" {
+			v.Value = contentType
+		}
+		if err := w.WriteField(v.Key, v.Value); err != nil {
+			return err
+		}
 	}
 
-	return fileBody, writer, s, nil
+	filePart, err := w.CreateFormFile("file", o.fileName())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(filePart, file); err != nil {
+		return err
+	}
+	return w.Close()
+}
 
+func (o *sendFileToS3Opts) fileName() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	if f, ok := o.File.(*os.File); ok {
+		return filepath.Base(f.Name())
+	}
+	return "file"
 }
 
 func (o *sendFileToS3Opts) httpMethod() string {
@@ -212,6 +384,21 @@ func (o *sendFileToS3Opts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *sendFileToS3Opts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *sendFileToS3Opts) retryPolicy() RetryPolicy {
+	// the file can't be read again without rewinding it
+	if _, ok := o.File.(io.Seeker); !ok {
+		return RetryPolicy{}
+	}
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSendFileToS3Response is the File Upload API Response for Get Spaces
 type PNSendFileToS3Response struct {
 }
@@ -0,0 +1,176 @@
+package pubnub
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// readMultipartBody returns the form fields and the file of a multipart body.
+func readMultipartBody(t *testing.T, body io.Reader, contentType string) (map[string]string, []byte) {
+	_, params, err := mime.ParseMediaType(contentType)
+	assert.Nil(t, err)
+
+	fields := map[string]string{}
+	var file []byte
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err) {
+			break
+		}
+		content, err := ioutil.ReadAll(part)
+		assert.Nil(t, err)
+		if part.FormName() == "file" {
+			file = content
+		} else {
+			fields[part.FormName()] = string(content)
+		}
+	}
+	return fields, file
+}
+
+// onlyReader hides the other methods of a reader.
+type onlyReader struct {
+	io.Reader
+}
+
+func TestSendFileToS3StreamBody(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+
+	content := strings.Repeat("file content ", 1000)
+	file, err := ioutil.TempFile("", "pubnub-upload")
+	assert.Nil(err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	_, err = file.WriteString(content)
+	assert.Nil(err)
+	_, err = file.Seek(0, io.SeekStart)
+	assert.Nil(err)
+
+	cases := []struct {
+		name    string
+		builder func(b *sendFileToS3Builder)
+	}{
+		{"file", func(b *sendFileToS3Builder) { b.File(file) }},
+		{"in-memory", func(b *sendFileToS3Builder) { b.File(strings.NewReader(content)) }},
+		{"size", func(b *sendFileToS3Builder) { b.File(onlyReader{strings.NewReader(content)}).Size(int64(len(content))) }},
+	}
+
+	for _, c := range cases {
+		b := newSendFileToS3Builder(pn).FileUploadRequestData(PNFileUploadRequest{
+			FormFields: []PNFormField{{Key: "key", Value: "value"}, {Key: "Content-Type", Value: ""}},
+		})
+		c.builder(b)
+
+		body, contentType, length, err := b.opts.streamBodyMultipartFileUpload()
+		if !assert.Nil(err, c.name) {
+			continue
+		}
+		_, isPipe := body.(*io.PipeReader)
+		assert.True(isPipe, c.name)
+
+		raw, err := ioutil.ReadAll(body)
+		assert.Nil(err, c.name)
+		assert.Equal(int64(len(raw)), length, c.name)
+
+		fields, uploaded := readMultipartBody(t, bytes.NewReader(raw), contentType)
+		assert.Equal("value", fields["key"], c.name)
+		assert.Equal("text/plain; charset=utf-8", fields["Content-Type"], c.name)
+		assert.Equal(content, string(uploaded), c.name)
+	}
+
+	// the content type set overrides the detected one
+	b := newSendFileToS3Builder(pn).File(strings.NewReader(content)).ContentType("application/x-custom").
+		FileUploadRequestData(PNFileUploadRequest{FormFields: []PNFormField{{Key: "Content-Type", Value: ""}}})
+	body, contentType, _, err := b.opts.streamBodyMultipartFileUpload()
+	assert.Nil(err)
+	fields, _ := readMultipartBody(t, body, contentType)
+	assert.Equal("application/x-custom", fields["Content-Type"])
+
+	_, _, err = newSendFileToS3Builder(pn).Execute()
+	assert.Contains(err.Error(), StrMissingFile)
+}
+
+func TestSendFileUnknownSize(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+	requests := map[string]int{}
+	pn.SetClient(newSendFileClient(requests, 0))
+
+	// a reader of unknown size isn't buffered, no upload URL is requested
+	_, _, err := pn.SendFile().Channel("ch").Name("name").File(onlyReader{strings.NewReader("content")}).Execute()
+	assert.Contains(err.Error(), StrMissingFileSize)
+	assert.Empty(requests)
+
+	// the encrypted size of the files of a custom cryptor is unknown
+	pn.Config.CryptoModule = crypto.NewCryptoModule(opaqueCryptor{})
+	_, _, err = pn.SendFile().Channel("ch").Name("name").File(strings.NewReader("content")).Execute()
+	assert.Contains(err.Error(), StrUnknownEncryptedFileSize)
+	assert.Empty(requests)
+}
+
+// opaqueCryptor is a Cryptor which isn't a crypto.StreamSizer.
+type opaqueCryptor struct {
+	crypto.Cryptor
+}
+
+func (opaqueCryptor) ID() string {
+	return "OPAQ"
+}
+
+func TestSendFileToS3Retries(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	content := strings.Repeat("file content ", 1000)
+	var uploads []string
+	codes := []int{500, 204}
+	pn.SetClient(&http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		raw, err := ioutil.ReadAll(req.Body)
+		assert.Nil(err)
+		assert.Equal(req.ContentLength, int64(len(raw)))
+		_, uploaded := readMultipartBody(t, bytes.NewReader(raw), req.Header.Get("Content-Type"))
+		uploads = append(uploads, string(uploaded))
+
+		code := codes[0]
+		codes = codes[1:]
+		return &http.Response{
+			StatusCode: code,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	})})
+
+	// the retry rewinds the reader
+	_, status, _ := newSendFileToS3Builder(pn).File(strings.NewReader(content)).
+		FileUploadRequestData(PNFileUploadRequest{URL: "https://storage.example.com/upload"}).Execute()
+	assert.Equal(204, status.StatusCode)
+	assert.Equal([]string{content, content}, uploads)
+
+	// a reader which can't be rewound isn't retried
+	uploads, codes = nil, []int{500, 204}
+	_, status, _ = newSendFileToS3Builder(pn).File(onlyReader{strings.NewReader(content)}).Size(int64(len(content))).
+		FileUploadRequestData(PNFileUploadRequest{URL: "https://storage.example.com/upload"}).Execute()
+	assert.Equal(500, status.StatusCode)
+	assert.Equal([]string{content}, uploads)
+}
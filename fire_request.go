@@ -28,6 +28,7 @@ type fireOpts struct {
 	Transport      http.RoundTripper
 	ctx            Context
 	QueryParam     map[string]string
+	RetryPolicy    *RetryPolicy
 	// nil hacks
 	setTTL         bool
 	setShouldStore bool
@@ -116,10 +117,21 @@ func (b *fireBuilder) QueryParam(queryParam map[string]string) *fireBuilder {
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *fireBuilder) RetryPolicy(policy RetryPolicy) *fireBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Fire request.
 func (b *fireBuilder) Execute() (*PublishResponse, StatusResponse, error) {
 	b.opts.ShouldStore = false
 	b.opts.DoNotReplicate = true
+	if status, err := b.opts.pubnub.publishRateLimiter.acquire(b.opts.ctx, b.opts.Channel, PNFireOperation); err != nil {
+		return emptyPublishResponse, status, err
+	}
+
 	rawJSON, status, err := executeRequest(b.opts)
 	if err != nil {
 		return emptyPublishResponse, status, err
@@ -169,20 +181,22 @@ func (o *fireOpts) buildPath() (string, error) {
 			"0"), nil
 	}
 
-	var message []byte
-	var err error
-
-	if cipherKey := o.pubnub.Config.CipherKey; cipherKey != "" {
-		msg := utils.EncryptString(cipherKey, string(message), o.pubnub.Config.UseRandomInitializationVector)
-
-		o.Message = []byte(msg)
-	}
-
-	message, err = utils.ValueAsString(o.Message)
+	message, err := valueAsString(o.pubnub.Config.serializer(), o.Message)
 	if err != nil {
 		return "", err
 	}
 
+	if module := o.pubnub.Config.cryptoModule(); module != nil {
+		enc, err := encryptString(module, string(message))
+		if err != nil {
+			return "", err
+		}
+		message, err = utils.ValueAsString(enc)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	return fmt.Sprintf(publishGetPath,
 		o.pubnub.Config.PublishKey,
 		o.pubnub.Config.SubscribeKey,
@@ -195,7 +209,7 @@ func (o *fireOpts) buildQuery() (*url.Values, error) {
 	q := defaultQuery(o.pubnub.Config.UUID, o.pubnub.telemetryManager)
 
 	if o.Meta != nil {
-		meta, err := utils.ValueAsString(o.Meta)
+		meta, err := valueAsString(o.pubnub.Config.serializer(), o.Meta)
 		if err != nil {
 			return &url.Values{}, err
 		}
@@ -227,7 +241,7 @@ func (o *fireOpts) buildBody() ([]byte, error) {
 		var msg []byte
 
 		if o.Serialize {
-			m, err := utils.ValueAsString(o.Message)
+			m, err := valueAsString(o.pubnub.Config.serializer(), o.Message)
 			if err != nil {
 				return []byte{}, err
 			}
@@ -241,8 +255,11 @@ func (o *fireOpts) buildBody() ([]byte, error) {
 			}
 		}
 
-		if cipherKey := o.pubnub.Config.CipherKey; cipherKey != "" {
-			enc := utils.EncryptString(cipherKey, string(msg), o.pubnub.Config.UseRandomInitializationVector)
+		if module := o.pubnub.Config.cryptoModule(); module != nil {
+			enc, err := encryptString(module, string(msg))
+			if err != nil {
+				return []byte{}, err
+			}
 			msg, err := utils.ValueAsString(enc)
 			if err != nil {
 				return []byte{}, err
@@ -288,3 +305,14 @@ func (o *fireOpts) telemetryManager() *TelemetryManager {
 func (o *fireOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *fireOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *fireOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
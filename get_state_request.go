@@ -66,6 +66,13 @@ func (b *getStateBuilder) QueryParam(queryParam map[string]string) *getStateBuil
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getStateBuilder) RetryPolicy(policy RetryPolicy) *getStateBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // UUID sets the UUID for the Get State request.
 func (b *getStateBuilder) UUID(uuid string) *getStateBuilder {
 	b.opts.UUID = uuid
@@ -102,6 +109,8 @@ type getStateOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -202,6 +211,17 @@ func (o *getStateOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getStateOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getStateOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // GetStateResponse is the struct returned when the Execute function of GetState is called.
 type GetStateResponse struct {
 	State map[string]interface{}
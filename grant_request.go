@@ -145,6 +145,13 @@ func (b *grantBuilder) QueryParam(queryParam map[string]string) *grantBuilder {
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *grantBuilder) RetryPolicy(policy RetryPolicy) *grantBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Grant request.
 func (b *grantBuilder) Execute() (*GrantResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -164,6 +171,7 @@ type grantOpts struct {
 	ChannelGroups []string
 	UUIDs         []string
 	QueryParam    map[string]string
+	RetryPolicy   *RetryPolicy
 	Meta          map[string]interface{}
 
 	// Stringified permissions
@@ -340,6 +348,17 @@ func (o *grantOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *grantOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *grantOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // GrantResponse is the struct returned when the Execute function of Grant is called.
 type GrantResponse struct {
 	Level        string
@@ -62,7 +62,7 @@ func (b *grantTokenBuilder) AuthorizedUUID(uuid string) *grantTokenBuilder {
 	return b
 }
 
-//Channels sets the Channels for the Grant request.
+// Channels sets the Channels for the Grant request.
 func (b *grantTokenBuilder) Channels(channels map[string]ChannelPermissions) *grantTokenBuilder {
 	b.opts.Channels = channels
 
@@ -116,6 +116,13 @@ func (b *grantTokenBuilder) QueryParam(queryParam map[string]string) *grantToken
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *grantTokenBuilder) RetryPolicy(policy RetryPolicy) *grantTokenBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Grant request.
 func (b *grantTokenBuilder) Execute() (*PNGrantTokenResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -138,6 +145,7 @@ type grantTokenOpts struct {
 	ChannelGroupsPattern map[string]GroupPermissions
 	UUIDsPattern         map[string]UUIDPermissions
 	QueryParam           map[string]string
+	RetryPolicy          *RetryPolicy
 	Meta                 map[string]interface{}
 	AuthorizedUUID       string
 
@@ -356,6 +364,17 @@ func (o *grantTokenOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *grantTokenOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *grantTokenOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGrantTokenData is the struct used to decode the server response
 type PNGrantTokenData struct {
 	Message string `json:"message"`
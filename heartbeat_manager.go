@@ -22,6 +22,9 @@ type HeartbeatManager struct {
 	hbRunning                 bool
 	queryParam                map[string]string
 	state                     map[string]interface{}
+	// set after a failed heartbeat, the presence state is restored after
+	// the next successful one.
+	stateLost bool
 }
 
 func newHeartbeatManager(pn *PubNub, context Context) *HeartbeatManager {
@@ -204,8 +207,7 @@ func (m *HeartbeatManager) performHeartbeatLoop() error {
 
 	if (len(presenceChannels) == 0) && (len(presenceGroups) == 0) {
 		m.pubnub.Config.Log.Println("performHeartbeatLoop: count presenceChannels, presenceGroups nil")
-		presenceChannels = m.pubnub.subscriptionManager.stateManager.prepareChannelList(false)
-		presenceGroups = m.pubnub.subscriptionManager.stateManager.prepareGroupList(false)
+		presenceChannels, presenceGroups = m.pubnub.subscriptionManager.stateManager.prepareHeartbeatLists()
 		stateStorage = m.pubnub.subscriptionManager.stateManager.createStatePayload()
 		queryParam = nil
 
@@ -228,18 +230,31 @@ func (m *HeartbeatManager) performHeartbeatLoop() error {
 	if err != nil {
 
 		pnStatus := &PNStatus{
-			Operation: PNHeartBeatOperation,
-			Category:  PNBadRequestCategory,
-			Error:     true,
-			ErrorData: err,
+			Operation:  PNHeartBeatOperation,
+			Category:   categoryForError(err),
+			Error:      true,
+			ErrorData:  err,
+			StatusCode: status.StatusCode,
 		}
 		m.pubnub.Config.Log.Println("performHeartbeatLoop: err", err, pnStatus)
 
 		m.pubnub.subscriptionManager.listenerManager.announceStatus(pnStatus)
 
+		m.Lock()
+		m.stateLost = true
+		m.Unlock()
+
 		return err
 	}
 
+	m.Lock()
+	stateLost := m.stateLost
+	m.stateLost = false
+	m.Unlock()
+	if stateLost {
+		go m.pubnub.subscriptionManager.restoreState("heartbeat recovered")
+	}
+
 	pnStatus := &PNStatus{
 		Category:   PNUnknownCategory,
 		Error:      false,
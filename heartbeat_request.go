@@ -141,7 +141,7 @@ func (o *heartbeatOpts) buildQuery() (*url.Values, error) {
 	}
 
 	if o.State != nil {
-		state, err := utils.ValueAsString(o.State)
+		state, err := valueAsString(o.pubnub.Config.serializer(), o.State)
 		if err != nil {
 			return &url.Values{}, err
 		}
@@ -194,3 +194,13 @@ func (o *heartbeatOpts) telemetryManager() *TelemetryManager {
 func (o *heartbeatOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *heartbeatOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+// retryPolicy disables the retries, the heartbeat requests are repeated by their
+// managers.
+func (o *heartbeatOpts) retryPolicy() RetryPolicy {
+	return RetryPolicy{}
+}
@@ -82,6 +82,13 @@ func (b *hereNowBuilder) QueryParam(queryParam map[string]string) *hereNowBuilde
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *hereNowBuilder) RetryPolicy(policy RetryPolicy) *hereNowBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the HereNow request.
 func (b *hereNowBuilder) Execute() (*HereNowResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -105,6 +112,8 @@ type hereNowOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -209,6 +218,17 @@ func (o *hereNowOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *hereNowOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *hereNowOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // HereNowResponse is the struct returned when the Execute function of HereNow is called.
 type HereNowResponse struct {
 	TotalChannels  int
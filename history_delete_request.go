@@ -69,6 +69,13 @@ func (b *historyDeleteBuilder) QueryParam(queryParam map[string]string) *history
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *historyDeleteBuilder) RetryPolicy(policy RetryPolicy) *historyDeleteBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the DeleteMessages request.
 func (b *historyDeleteBuilder) Transport(tr http.RoundTripper) *historyDeleteBuilder {
 	b.opts.Transport = tr
@@ -98,6 +105,8 @@ type historyDeleteOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -191,6 +200,17 @@ func (o *historyDeleteOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *historyDeleteOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *historyDeleteOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // HistoryDeleteResponse is the struct returned when Delete Messages is called.
 type HistoryDeleteResponse struct {
 }
@@ -98,6 +98,13 @@ func (b *historyBuilder) QueryParam(queryParam map[string]string) *historyBuilde
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *historyBuilder) RetryPolicy(policy RetryPolicy) *historyBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the History request.
 func (b *historyBuilder) Transport(tr http.RoundTripper) *historyBuilder {
 	b.opts.Transport = tr
@@ -139,6 +146,8 @@ type historyOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -238,6 +247,17 @@ func (o *historyOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *historyOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *historyOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // HistoryResponse is used to store the response from the History request.
 type HistoryResponse struct {
 	Messages       []HistoryResponseItem
@@ -268,16 +288,34 @@ func getHistoryItemsWithoutTimetoken(historyResponseRaw []byte, o *historyOpts,
 		return nil, e
 	}
 
+	// the messages are decoded again by a custom Serializer
+	var rawItems []json.RawMessage
+	if o.pubnub.Config.Serializer != nil {
+		json.Unmarshal(historyResponseRaw, &rawItems)
+	}
+
 	items := make([]HistoryResponseItem, len(historyResponseItems))
 
 	for i, v := range historyResponseItems {
 		o.pubnub.Config.Log.Println(v)
-		items[i].Message, _ = parseCipherInterface(v, o.pubnub.Config)
+		var raw []byte
+		if i < len(rawItems) {
+			raw = rawItems[i]
+		}
+		items[i].Message, _ = decodeMessage(raw, v, o.pubnub.Config)
 	}
 	return items, nil
 }
 
 func getHistoryItemsWithTimetoken(historyResponseItems []HistoryResponseItem, o *historyOpts, historyResponseRaw []byte, jsonBytes []byte) ([]HistoryResponseItem, *pnerr.ResponseParsingError) {
+	// the messages are decoded again by a custom Serializer
+	var rawItems []struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if o.pubnub.Config.Serializer != nil {
+		json.Unmarshal(historyResponseRaw, &rawItems)
+	}
+
 	items := make([]HistoryResponseItem, len(historyResponseItems))
 
 	b := false
@@ -285,7 +323,11 @@ func getHistoryItemsWithTimetoken(historyResponseItems []HistoryResponseItem, o
 	for i, v := range historyResponseItems {
 		if v.Message != nil {
 			o.pubnub.Config.Log.Println(v.Message)
-			items[i].Message, _ = parseCipherInterface(v.Message, o.pubnub.Config)
+			var raw []byte
+			if i < len(rawItems) {
+				raw = rawItems[i].Message
+			}
+			items[i].Message, _ = decodeMessage(raw, v.Message, o.pubnub.Config)
 
 			o.pubnub.Config.Log.Println(v.Timetoken)
 			items[i].Timetoken = v.Timetoken
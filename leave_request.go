@@ -57,6 +57,13 @@ func (b *leaveBuilder) QueryParam(queryParam map[string]string) *leaveBuilder {
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *leaveBuilder) RetryPolicy(policy RetryPolicy) *leaveBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Leave request.
 func (b *leaveBuilder) Execute() (StatusResponse, error) {
 	_, status, err := executeRequest(b.opts)
@@ -71,6 +78,7 @@ type leaveOpts struct {
 	Channels      []string
 	ChannelGroups []string
 	QueryParam    map[string]string
+	RetryPolicy   *RetryPolicy
 
 	pubnub *PubNub
 	ctx    Context
@@ -150,3 +158,14 @@ func (o *leaveOpts) telemetryManager() *TelemetryManager {
 func (o *leaveOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *leaveOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *leaveOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
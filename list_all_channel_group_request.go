@@ -58,6 +58,13 @@ func (b *allChannelGroupBuilder) QueryParam(queryParam map[string]string) *allCh
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *allChannelGroupBuilder) RetryPolicy(policy RetryPolicy) *allChannelGroupBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the ListChannelsInChannelGroup request.
 func (b *allChannelGroupBuilder) Execute() (
 	*AllChannelGroupResponse, StatusResponse, error) {
@@ -76,6 +83,8 @@ type allChannelGroupOpts struct {
 	QueryParam   map[string]string
 	Transport    http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -155,6 +164,17 @@ func (o *allChannelGroupOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *allChannelGroupOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *allChannelGroupOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // AllChannelGroupResponse is the struct returned when the Execute function of List All Channel Groups is called.
 type AllChannelGroupResponse struct {
 	Channels     []string
@@ -76,6 +76,13 @@ func (b *listPushProvisionsRequestBuilder) QueryParam(queryParam map[string]stri
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *listPushProvisionsRequestBuilder) RetryPolicy(policy RetryPolicy) *listPushProvisionsRequestBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the List Push Provisions request.
 func (b *listPushProvisionsRequestBuilder) Execute() (
 	*ListPushProvisionsRequestResponse, StatusResponse, error) {
@@ -123,6 +130,8 @@ type listPushProvisionsRequestOpts struct {
 	Topic           string
 	Environment     PNPushEnvironment
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -224,3 +233,14 @@ func (o *listPushProvisionsRequestOpts) telemetryManager() *TelemetryManager {
 func (o *listPushProvisionsRequestOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *listPushProvisionsRequestOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *listPushProvisionsRequestOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
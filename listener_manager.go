@@ -1,6 +1,7 @@
 package pubnub
 
 import (
+	"fmt"
 	"sync"
 )
 
@@ -32,11 +33,63 @@ func NewListener() *Listener {
 	}
 }
 
+// EventListener is a callback based alternative to Listener. Only the
+// callbacks which are set are called, events without a callback are dropped.
+// Callbacks are called one at a time from the delivery goroutine of the
+// listener, a slow callback delays the following events of the same listener.
+type EventListener struct {
+	OnStatus        func(status *PNStatus)
+	OnMessage       func(message *PNMessage)
+	OnPresence      func(presence *PNPresence)
+	OnSignal        func(signal *PNMessage)
+	OnFile          func(file *PNFilesEvent)
+	OnObjectEvent   func(event *PNObjectEvent)
+	OnMessageAction func(event *PNMessageActionsEvent)
+}
+
+// PNObjectEvent wraps the Objects events for EventListener.OnObjectEvent.
+// Depending on Type one of UUIDEvent, ChannelEvent or MembershipEvent is set.
+type PNObjectEvent struct {
+	Type            PNObjectsEventType
+	UUIDEvent       *PNUUIDEvent
+	ChannelEvent    *PNChannelEvent
+	MembershipEvent *PNMembershipEvent
+}
+
+// listenerEvent identifies the channel of a Listener an event is delivered to.
+type listenerEvent int
+
+const (
+	listenerStatusEvent listenerEvent = iota
+	listenerMessageEvent
+	listenerPresenceEvent
+	listenerSignalEvent
+	listenerUUIDEvent
+	listenerChannelEvent
+	listenerMembershipEvent
+	listenerMessageActionsEvent
+	listenerFileEvent
+)
+
+// listenerScope limits the events delivered to a listener, see Subscription
+// and SubscriptionSet.
+type listenerScope interface {
+	matchesEvent(channel, subscription string, presence bool) bool
+	matchesStatus(status *PNStatus) bool
+}
+
 // ListenerManager is used in the internal handling of listeners.
+// Every channel of a Listener and every EventListener gets its own bounded
+// delivery queue, see listenerQueue. The events of the same type are
+// delivered to a listener in the order they were announced, a channel which
+// isn't read doesn't hold back the other channels of the Listener.
 type ListenerManager struct {
 	sync.RWMutex
 	ctx                  Context
 	listeners            map[*Listener]bool
+	listenerQueues       map[*Listener]map[listenerEvent]*listenerQueue
+	eventListeners       map[*EventListener]*listenerQueue
+	scopes               map[interface{}]listenerScope
 	exitListener         chan bool
 	exitListenerAnnounce chan bool
 	pubnub               *PubNub
@@ -45,6 +98,9 @@ type ListenerManager struct {
 func newListenerManager(ctx Context, pn *PubNub) *ListenerManager {
 	return &ListenerManager{
 		listeners:            make(map[*Listener]bool, 2),
+		listenerQueues:       make(map[*Listener]map[listenerEvent]*listenerQueue, 2),
+		eventListeners:       make(map[*EventListener]*listenerQueue, 2),
+		scopes:               make(map[interface{}]listenerScope),
 		ctx:                  ctx,
 		exitListener:         make(chan bool),
 		exitListenerAnnounce: make(chan bool),
@@ -52,22 +108,149 @@ func newListenerManager(ctx Context, pn *PubNub) *ListenerManager {
 	}
 }
 
+func newQueueOverflowStatus(dropped int) *PNStatus {
+	return &PNStatus{
+		Category:  PNListenerQueueOverflowCategory,
+		Error:     true,
+		ErrorData: fmt.Errorf("listener queue overflow, %d events dropped", dropped),
+	}
+}
+
+// newQueue creates a delivery queue with the capacity and the overflow policy
+// of the config. Zero values fall back to the defaults of NewConfig.
+func (m *ListenerManager) newQueue(overflow func(dropped int) listenerDelivery) *listenerQueue {
+	m.pubnub.Config.RLock()
+	size := m.pubnub.Config.ListenerQueueSize
+	policy := m.pubnub.Config.ListenerOverflowPolicy
+	m.pubnub.Config.RUnlock()
+
+	if size <= 0 {
+		size = defaultListenerQueueSize
+	}
+	if policy == 0 {
+		policy = PNListenerOverflowBlock
+	}
+
+	return newListenerQueue(size, policy, overflow)
+}
+
+// statusDelivery delivers status to the Status channel of listener.
+func (m *ListenerManager) statusDelivery(listener *Listener, status *PNStatus) listenerDelivery {
+	return func(done <-chan struct{}) {
+		select {
+		case <-m.exitListener:
+			m.pubnub.Config.Log.Println("announceStatus exitListener")
+		case <-done:
+		case listener.Status <- status:
+		}
+	}
+}
+
+// listenerQueueLocked returns the queue of the given channel of a Listener,
+// creating it on first use. It returns nil if the Listener was removed.
+// m must be locked.
+func (m *ListenerManager) listenerQueueLocked(listener *Listener, event listenerEvent) *listenerQueue {
+	queues, ok := m.listenerQueues[listener]
+	if !ok {
+		return nil
+	}
+	if q, ok := queues[event]; ok {
+		return q
+	}
+
+	var q *listenerQueue
+	if event == listenerStatusEvent {
+		q = m.newQueue(func(dropped int) listenerDelivery {
+			return m.statusDelivery(listener, newQueueOverflowStatus(dropped))
+		})
+	} else {
+		// the overflow of the other channels goes through the status queue,
+		// the channel doesn't have to wait for Status to be read.
+		q = m.newQueue(func(dropped int) listenerDelivery {
+			return func(done <-chan struct{}) {
+				m.Lock()
+				statusQueue := m.listenerQueueLocked(listener, listenerStatusEvent)
+				m.Unlock()
+
+				if statusQueue != nil {
+					statusQueue.push(m.statusDelivery(listener, newQueueOverflowStatus(dropped)))
+				}
+			}
+		})
+	}
+	queues[event] = q
+
+	return q
+}
+
 func (m *ListenerManager) addListener(listener *Listener) {
 	m.Lock()
-
+	if _, ok := m.listenerQueues[listener]; !ok {
+		m.listenerQueues[listener] = make(map[listenerEvent]*listenerQueue)
+	}
 	m.listeners[listener] = true
 	m.Unlock()
 }
 
+// addScopedListener adds a listener which only receives the events matching
+// scope.
+func (m *ListenerManager) addScopedListener(listener *Listener, scope listenerScope) {
+	m.addListener(listener)
+
+	m.Lock()
+	m.scopes[listener] = scope
+	m.Unlock()
+}
+
 func (m *ListenerManager) removeListener(listener *Listener) {
 	m.pubnub.Config.Log.Println("before removeListener")
 	m.Lock()
 	m.pubnub.Config.Log.Println("in removeListener lock")
+	for _, q := range m.listenerQueues[listener] {
+		q.close()
+	}
+	delete(m.listenerQueues, listener)
 	delete(m.listeners, listener)
+	delete(m.scopes, listener)
 	m.Unlock()
 	m.pubnub.Config.Log.Println("after removeListener")
 }
 
+func (m *ListenerManager) addEventListener(listener *EventListener) {
+	m.Lock()
+	if _, ok := m.eventListeners[listener]; !ok {
+		m.eventListeners[listener] = m.newQueue(func(dropped int) listenerDelivery {
+			status := newQueueOverflowStatus(dropped)
+			return func(done <-chan struct{}) {
+				if listener.OnStatus != nil {
+					listener.OnStatus(status)
+				}
+			}
+		})
+	}
+	m.Unlock()
+}
+
+// addScopedEventListener adds a callback based listener which only receives
+// the events matching scope.
+func (m *ListenerManager) addScopedEventListener(listener *EventListener, scope listenerScope) {
+	m.addEventListener(listener)
+
+	m.Lock()
+	m.scopes[listener] = scope
+	m.Unlock()
+}
+
+func (m *ListenerManager) removeEventListener(listener *EventListener) {
+	m.Lock()
+	if q, ok := m.eventListeners[listener]; ok {
+		q.close()
+		delete(m.eventListeners, listener)
+	}
+	delete(m.scopes, listener)
+	m.Unlock()
+}
+
 func (m *ListenerManager) removeAllListeners() {
 	m.pubnub.Config.Log.Println("in removeAllListeners")
 	m.Lock()
@@ -75,6 +258,19 @@ func (m *ListenerManager) removeAllListeners() {
 	for l := range lis {
 		delete(m.listeners, l)
 	}
+	for l, queues := range m.listenerQueues {
+		for _, q := range queues {
+			q.close()
+		}
+		delete(m.listenerQueues, l)
+	}
+	for l, q := range m.eventListeners {
+		q.close()
+		delete(m.eventListeners, l)
+	}
+	for l := range m.scopes {
+		delete(m.scopes, l)
+	}
 	m.Unlock()
 }
 
@@ -88,160 +284,219 @@ func (m *ListenerManager) copyListeners() map[*Listener]bool {
 	return lis
 }
 
+func (m *ListenerManager) copyEventListeners() []*EventListener {
+	m.RLock()
+	lis := make([]*EventListener, 0, len(m.eventListeners))
+	for l := range m.eventListeners {
+		lis = append(lis, l)
+	}
+	m.RUnlock()
+	return lis
+}
+
+// inScopeLocked reports whether listener, a *Listener or an *EventListener,
+// gets an event. Listeners without a scope get all the events.
+// m must be locked.
+func (m *ListenerManager) inScopeLocked(listener interface{}, match func(scope listenerScope) bool) bool {
+	scope, ok := m.scopes[listener]
+	return !ok || match(scope)
+}
+
+// eventMatcher returns the scope matcher of an event received on channel
+// through subscription.
+func eventMatcher(channel, subscription string, presence bool) func(scope listenerScope) bool {
+	return func(scope listenerScope) bool {
+		return scope.matchesEvent(channel, subscription, presence)
+	}
+}
+
+// announce pushes an event to the queue of every listener in scope of the
+// event according to match.
+// toListener delivers the event to the channel of a Listener identified by
+// event.
+// toEventListener returns the delivery to an EventListener, or nil if the
+// EventListener has no callback for the event, in which case it's dropped.
+func (m *ListenerManager) announce(event listenerEvent, match func(scope listenerScope) bool,
+	toListener func(l *Listener, done <-chan struct{}), toEventListener func(l *EventListener) func()) {
+	type queued struct {
+		queue    *listenerQueue
+		delivery listenerDelivery
+	}
+
+	m.Lock()
+	deliveries := make([]queued, 0, len(m.listenerQueues)+len(m.eventListeners))
+	for l := range m.listenerQueues {
+		if !m.inScopeLocked(l, match) {
+			continue
+		}
+		l := l
+		deliveries = append(deliveries, queued{m.listenerQueueLocked(l, event), func(done <-chan struct{}) {
+			toListener(l, done)
+		}})
+	}
+	for l, q := range m.eventListeners {
+		if !m.inScopeLocked(l, match) {
+			continue
+		}
+		if f := toEventListener(l); f != nil {
+			deliveries = append(deliveries, queued{q, func(done <-chan struct{}) {
+				f()
+			}})
+		}
+	}
+	m.Unlock()
+
+	for _, d := range deliveries {
+		if !d.queue.push(d.delivery) {
+			m.pubnub.Config.Log.Println("listener queue: event dropped")
+		}
+	}
+}
+
 func (m *ListenerManager) announceStatus(status *PNStatus) {
-	go func() {
-		lis := m.copyListeners()
-	AnnounceStatusLabel:
-		for l := range lis {
-			select {
-			case <-m.exitListener:
-				m.pubnub.Config.Log.Println("announceStatus exitListener")
-				break AnnounceStatusLabel
-			case l.Status <- status:
-			}
+	m.announce(listenerStatusEvent, func(scope listenerScope) bool {
+		return scope.matchesStatus(status)
+	}, func(l *Listener, done <-chan struct{}) {
+		m.statusDelivery(l, status)(done)
+	}, func(l *EventListener) func() {
+		if l.OnStatus == nil {
+			return nil
 		}
-		m.pubnub.Config.Log.Println("announceStatus exit")
-	}()
+		return func() { l.OnStatus(status) }
+	})
 }
 
 func (m *ListenerManager) announceMessage(message *PNMessage) {
-	go func() {
-		lis := m.copyListeners()
-	AnnounceMessageLabel:
-		for l := range lis {
-			select {
-			case <-m.exitListenerAnnounce:
-				m.pubnub.Config.Log.Println("announceMessage exitListenerAnnounce")
-				break AnnounceMessageLabel
-			case l.Message <- message:
-			}
+	m.announce(listenerMessageEvent, eventMatcher(message.Channel, message.Subscription, false), func(l *Listener, done <-chan struct{}) {
+		select {
+		case <-m.exitListenerAnnounce:
+			m.pubnub.Config.Log.Println("announceMessage exitListenerAnnounce")
+		case <-done:
+		case l.Message <- message:
 		}
-
-	}()
+	}, func(l *EventListener) func() {
+		if l.OnMessage == nil {
+			return nil
+		}
+		return func() { l.OnMessage(message) }
+	})
 }
 
 func (m *ListenerManager) announceSignal(message *PNMessage) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceSignalLabel:
-		for l := range lis {
-			select {
-			case <-m.exitListener:
-				m.pubnub.Config.Log.Println("announceSignal exitListener")
-				break AnnounceSignalLabel
+	m.announce(listenerSignalEvent, eventMatcher(message.Channel, message.Subscription, false), func(l *Listener, done <-chan struct{}) {
+		select {
+		case <-m.exitListener:
+			m.pubnub.Config.Log.Println("announceSignal exitListener")
+		case <-done:
+		case l.Signal <- message:
+		}
+	}, func(l *EventListener) func() {
+		if l.OnSignal == nil {
+			return nil
+		}
+		return func() { l.OnSignal(message) }
+	})
+}
 
-			case l.Signal <- message:
-			}
+func (m *ListenerManager) announceObjectEvent(event *PNObjectEvent, listenerEvent listenerEvent,
+	channel, subscription string, toListener func(l *Listener, done <-chan struct{})) {
+	m.announce(listenerEvent, eventMatcher(channel, subscription, false), toListener, func(l *EventListener) func() {
+		if l.OnObjectEvent == nil {
+			return nil
 		}
-	}()
+		return func() { l.OnObjectEvent(event) }
+	})
 }
 
 func (m *ListenerManager) announceUUIDEvent(message *PNUUIDEvent) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceUUIDEventLabel:
-		for l := range lis {
+	m.announceObjectEvent(&PNObjectEvent{Type: PNObjectsUUIDEvent, UUIDEvent: message}, listenerUUIDEvent,
+		message.Channel, message.Subscription,
+		func(l *Listener, done <-chan struct{}) {
 			select {
 			case <-m.exitListener:
 				m.pubnub.Config.Log.Println("announceUUIDEvent exitListener")
-				break AnnounceUUIDEventLabel
-
+			case <-done:
 			case l.UUIDEvent <- message:
 				m.pubnub.Config.Log.Println("l.UUIDEvent", message)
 			}
-		}
-	}()
+		})
 }
 
 func (m *ListenerManager) announceChannelEvent(message *PNChannelEvent) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceChannelEventLabel:
-		for l := range lis {
-			m.pubnub.Config.Log.Println("l.ChannelEvent", l)
+	m.announceObjectEvent(&PNObjectEvent{Type: PNObjectsChannelEvent, ChannelEvent: message}, listenerChannelEvent,
+		message.Channel, message.Subscription,
+		func(l *Listener, done <-chan struct{}) {
 			select {
 			case <-m.exitListener:
 				m.pubnub.Config.Log.Println("announceChannelEvent exitListener")
-				break AnnounceChannelEventLabel
-
+			case <-done:
 			case l.ChannelEvent <- message:
 				m.pubnub.Config.Log.Println("l.ChannelEvent", message)
 			}
-		}
-	}()
+		})
 }
 
 func (m *ListenerManager) announceMembershipEvent(message *PNMembershipEvent) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceMembershipEvent:
-		for l := range lis {
+	m.announceObjectEvent(&PNObjectEvent{Type: PNObjectsMembershipEvent, MembershipEvent: message}, listenerMembershipEvent,
+		message.Channel, message.Subscription,
+		func(l *Listener, done <-chan struct{}) {
 			select {
 			case <-m.exitListener:
 				m.pubnub.Config.Log.Println("announceMembershipEvent exitListener")
-				break AnnounceMembershipEvent
-
+			case <-done:
 			case l.MembershipEvent <- message:
 				m.pubnub.Config.Log.Println("l.MembershipEvent", message)
 			}
-		}
-	}()
+		})
 }
 
 func (m *ListenerManager) announceMessageActionsEvent(message *PNMessageActionsEvent) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceMessageActionsEvent:
-		for l := range lis {
-			select {
-			case <-m.exitListener:
-				m.pubnub.Config.Log.Println("announceMessageActionsEvent exitListener")
-				break AnnounceMessageActionsEvent
-
-			case l.MessageActionsEvent <- message:
-				m.pubnub.Config.Log.Println("l.MessageActionsEvent", message)
-			}
+	m.announce(listenerMessageActionsEvent, eventMatcher(message.Channel, message.Subscription, false), func(l *Listener, done <-chan struct{}) {
+		select {
+		case <-m.exitListener:
+			m.pubnub.Config.Log.Println("announceMessageActionsEvent exitListener")
+		case <-done:
+		case l.MessageActionsEvent <- message:
+			m.pubnub.Config.Log.Println("l.MessageActionsEvent", message)
 		}
-	}()
+	}, func(l *EventListener) func() {
+		if l.OnMessageAction == nil {
+			return nil
+		}
+		return func() { l.OnMessageAction(message) }
+	})
 }
 
 func (m *ListenerManager) announcePresence(presence *PNPresence) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnouncePresenceLabel:
-		for l := range lis {
-			select {
-			case <-m.exitListener:
-				m.pubnub.Config.Log.Println("announcePresence exitListener")
-				break AnnouncePresenceLabel
-
-			case l.Presence <- presence:
-			}
+	m.announce(listenerPresenceEvent, eventMatcher(presence.Channel, presence.Subscription, true), func(l *Listener, done <-chan struct{}) {
+		select {
+		case <-m.exitListener:
+			m.pubnub.Config.Log.Println("announcePresence exitListener")
+		case <-done:
+		case l.Presence <- presence:
 		}
-	}()
+	}, func(l *EventListener) func() {
+		if l.OnPresence == nil {
+			return nil
+		}
+		return func() { l.OnPresence(presence) }
+	})
 }
 
 func (m *ListenerManager) announceFile(file *PNFilesEvent) {
-	go func() {
-		lis := m.copyListeners()
-
-	AnnounceFileLabel:
-		for l := range lis {
-			select {
-			case <-m.exitListener:
-				m.pubnub.Config.Log.Println("announceFile exitListener")
-				break AnnounceFileLabel
-
-			case l.File <- file:
-			}
+	m.announce(listenerFileEvent, eventMatcher(file.Channel, file.Subscription, false), func(l *Listener, done <-chan struct{}) {
+		select {
+		case <-m.exitListener:
+			m.pubnub.Config.Log.Println("announceFile exitListener")
+		case <-done:
+		case l.File <- file:
 		}
-	}()
+	}, func(l *EventListener) func() {
+		if l.OnFile == nil {
+			return nil
+		}
+		return func() { l.OnFile(file) }
+	})
 }
 
 // PNStatus is the status struct
@@ -270,6 +525,19 @@ type PNMessage struct {
 	Subscription      string
 	Publisher         string
 	Timetoken         int64
+	CatchUp           bool // The message was fetched from history after a reconnection, see Config.CatchUpOnReconnect.
+	Envelope          PNEnvelope
+}
+
+// PNEnvelope is the metadata of the subscribe envelope a message, a signal or
+// a file event was delivered in. It's empty for the messages fetched by the catch-up.
+type PNEnvelope struct {
+	Shard             string
+	Flags             int
+	SubscribeKey      string
+	SequenceNumber    int // Sequence number set by the publisher, see Config.DetectSequenceGaps.
+	OriginationRegion int
+	PublishRegion     int
 }
 
 // PNPresence is the Message Response for Presence
@@ -360,4 +628,5 @@ type PNFilesEvent struct {
 	Subscription      string
 	Publisher         string
 	Timetoken         int64
+	Envelope          PNEnvelope
 }
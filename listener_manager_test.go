@@ -0,0 +1,252 @@
+package pubnub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventListenerOnlyRegisteredCallbacks(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	messages := make(chan *PNMessage, 1)
+	listener := &EventListener{
+		OnMessage: func(message *PNMessage) {
+			messages <- message
+		},
+	}
+	pn.AddEventListener(listener)
+
+	lm := pn.subscriptionManager.listenerManager
+	announced := make(chan bool)
+	go func() {
+		lm.announceStatus(&PNStatus{Category: PNConnectedCategory})
+		lm.announcePresence(&PNPresence{Event: "join"})
+		lm.announceSignal(&PNMessage{Message: "signal", Channel: "ch"})
+		lm.announceMessage(&PNMessage{Message: "hi", Channel: "ch"})
+		close(announced)
+	}()
+
+	select {
+	case <-announced:
+	case <-time.After(time.Second):
+		assert.Fail("announce didn't return")
+	}
+
+	select {
+	case m := <-messages:
+		assert.Equal("hi", m.Message)
+		assert.Equal("ch", m.Channel)
+	case <-time.After(2 * time.Second):
+		assert.Fail("message not delivered")
+	}
+
+	select {
+	case m := <-messages:
+		assert.Fail("unexpected event", "%v", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEventListenerIndependentOfListener(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	release := make(chan bool)
+	defer close(release)
+	pn.AddEventListener(&EventListener{
+		OnMessage: func(message *PNMessage) {
+			<-release
+		},
+	})
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	pn.subscriptionManager.listenerManager.announceMessage(&PNMessage{Message: "hi"})
+
+	select {
+	case m := <-listener.Message:
+		assert.Equal("hi", m.Message)
+	case <-time.After(2 * time.Second):
+		assert.Fail("blocked callback delayed the channel listener")
+	}
+}
+
+func TestEventListenerObjectEvent(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	events := make(chan *PNObjectEvent, 1)
+	pn.AddEventListener(&EventListener{
+		OnObjectEvent: func(event *PNObjectEvent) {
+			events <- event
+		},
+	})
+
+	pn.subscriptionManager.listenerManager.announceChannelEvent(&PNChannelEvent{ChannelID: "ch"})
+
+	select {
+	case e := <-events:
+		assert.Equal(PNObjectsEventType(PNObjectsChannelEvent), e.Type)
+		assert.Equal("ch", e.ChannelEvent.ChannelID)
+		assert.Nil(e.UUIDEvent)
+	case <-time.After(2 * time.Second):
+		assert.Fail("object event not delivered")
+	}
+}
+
+func TestRemoveEventListener(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	listener := &EventListener{
+		OnStatus: func(status *PNStatus) {},
+	}
+	pn.AddEventListener(listener)
+	assert.Equal(1, len(pn.subscriptionManager.listenerManager.copyEventListeners()))
+
+	pn.RemoveEventListener(listener)
+	assert.Equal(0, len(pn.subscriptionManager.listenerManager.copyEventListeners()))
+}
+
+func TestListenerDeliveryOrder(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	lm := pn.subscriptionManager.listenerManager
+	for i := int64(1); i <= 50; i++ {
+		lm.announceMessage(&PNMessage{Timetoken: i})
+	}
+
+	for i := int64(1); i <= 50; i++ {
+		select {
+		case m := <-listener.Message:
+			assert.Equal(i, m.Timetoken)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message not delivered")
+			return
+		}
+	}
+}
+
+// waitListenerQueueDrained waits until the delivery goroutine of the given
+// channel of listener took all the queued events.
+func waitListenerQueueDrained(t *testing.T, lm *ListenerManager, listener *Listener, event listenerEvent) {
+	deadline := time.After(2 * time.Second)
+	for {
+		lm.RLock()
+		q := lm.listenerQueues[listener][event]
+		lm.RUnlock()
+		if q != nil && q.pending() == 0 {
+			return
+		}
+		select {
+		case <-deadline:
+			assert.Fail(t, "listener queue wasn't drained")
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestListenerQueueOverflowAnnouncesStatus(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ListenerQueueSize = 2
+	config.ListenerOverflowPolicy = PNListenerOverflowStatus
+	pn := NewPubNub(config)
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	lm := pn.subscriptionManager.listenerManager
+
+	// the first message blocks the delivery goroutine on the unread channel,
+	// the next two fill the queue and the rest are dropped.
+	lm.announceMessage(&PNMessage{Timetoken: 1})
+	waitListenerQueueDrained(t, lm, listener, listenerMessageEvent)
+	for i := int64(2); i <= 10; i++ {
+		lm.announceMessage(&PNMessage{Timetoken: i})
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		select {
+		case m := <-listener.Message:
+			assert.Equal(i, m.Timetoken)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message not delivered")
+			return
+		}
+	}
+	waitListenerQueueDrained(t, lm, listener, listenerMessageEvent)
+	lm.announceMessage(&PNMessage{Timetoken: 11})
+
+	select {
+	case status := <-listener.Status:
+		assert.Equal(PNListenerQueueOverflowCategory, status.Category)
+		assert.Contains(status.ErrorData.Error(), "7 events dropped")
+	case <-time.After(2 * time.Second):
+		assert.Fail("overflow status not delivered")
+	}
+	select {
+	case m := <-listener.Message:
+		assert.Equal(int64(11), m.Timetoken)
+	case <-time.After(2 * time.Second):
+		assert.Fail("message not delivered")
+	}
+}
+
+func TestListenerUnreadChannelDoesntBlockOthers(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ListenerQueueSize = 2
+	config.ListenerOverflowPolicy = PNListenerOverflowDropNewest
+	pn := NewPubNub(config)
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	lm := pn.subscriptionManager.listenerManager
+
+	// Signal is never read, its queue overflows
+	announced := make(chan bool)
+	go func() {
+		for i := int64(1); i <= 10; i++ {
+			lm.announceSignal(&PNMessage{Timetoken: i})
+		}
+		close(announced)
+	}()
+	select {
+	case <-announced:
+	case <-time.After(2 * time.Second):
+		assert.Fail("announce blocked")
+		return
+	}
+
+	for i := int64(1); i <= 10; i++ {
+		lm.announceMessage(&PNMessage{Timetoken: i})
+		select {
+		case m := <-listener.Message:
+			assert.Equal(i, m.Timetoken)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message held back by the unread signal channel")
+			return
+		}
+	}
+}
+
+func TestListenerQueueZeroConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ListenerQueueSize = 0
+	config.ListenerOverflowPolicy = 0
+	pn := NewPubNub(config)
+
+	q := pn.subscriptionManager.listenerManager.newQueue(nil)
+	defer q.close()
+
+	assert.Equal(defaultListenerQueueSize, q.capacity)
+	assert.Equal(PNListenerOverflowBlock, q.policy)
+}
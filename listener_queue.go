@@ -0,0 +1,143 @@
+package pubnub
+
+import (
+	"sync"
+)
+
+// listenerDelivery delivers a single event to a listener. done is closed
+// when the queue is closed, deliveries which may block should select on it.
+type listenerDelivery func(done <-chan struct{})
+
+// listenerQueue is the bounded delivery queue of a single listener. Events are
+// delivered in the order they were pushed by a dedicated goroutine, the
+// behaviour when the queue is full depends on the ListenerOverflowPolicy.
+type listenerQueue struct {
+	sync.Mutex
+
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []listenerDelivery
+	capacity int
+	policy   ListenerOverflowPolicy
+	dropped  int
+	closed   bool
+	done     chan struct{}
+
+	// overflow builds the delivery of the status announcing the dropped
+	// events, used with PNListenerOverflowStatus.
+	overflow func(dropped int) listenerDelivery
+}
+
+func newListenerQueue(capacity int, policy ListenerOverflowPolicy,
+	overflow func(dropped int) listenerDelivery) *listenerQueue {
+	if capacity <= 0 {
+		capacity = defaultListenerQueueSize
+	}
+
+	q := &listenerQueue{
+		items:    make([]listenerDelivery, 0, capacity),
+		capacity: capacity,
+		policy:   policy,
+		done:     make(chan struct{}),
+		overflow: overflow,
+	}
+	q.notEmpty = sync.NewCond(q)
+	q.notFull = sync.NewCond(q)
+
+	go q.run()
+
+	return q
+}
+
+// push adds a delivery to the queue. It returns false if the delivery was
+// dropped.
+func (q *listenerQueue) push(delivery listenerDelivery) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	if len(q.items) >= q.capacity {
+		switch q.policy {
+		case PNListenerOverflowBlock:
+			for len(q.items) >= q.capacity && !q.closed {
+				q.notFull.Wait()
+			}
+			if q.closed {
+				return false
+			}
+		case PNListenerOverflowDropOldest:
+			q.items[0] = nil
+			q.items = q.items[1:]
+			q.dropped++
+		default:
+			// PNListenerOverflowDropNewest and PNListenerOverflowStatus
+			q.dropped++
+			return false
+		}
+	}
+
+	if q.dropped > 0 && q.policy == PNListenerOverflowStatus && q.overflow != nil {
+		// the status is allowed to exceed the capacity by one, it has to
+		// reach the listener before the events following the gap.
+		q.items = append(q.items, q.overflow(q.dropped))
+		q.dropped = 0
+	}
+
+	q.items = append(q.items, delivery)
+	q.notEmpty.Signal()
+
+	return true
+}
+
+func (q *listenerQueue) run() {
+	for {
+		q.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.closed {
+			q.Unlock()
+			return
+		}
+		delivery := q.items[0]
+		q.items[0] = nil
+		q.items = q.items[1:]
+		q.notFull.Broadcast()
+		q.Unlock()
+
+		delivery(q.done)
+	}
+}
+
+// close stops the delivery goroutine, pending events are discarded.
+func (q *listenerQueue) close() {
+	q.Lock()
+	if !q.closed {
+		q.closed = true
+		q.items = nil
+		close(q.done)
+		q.notEmpty.Broadcast()
+		q.notFull.Broadcast()
+	}
+	q.Unlock()
+}
+
+// droppedCount returns the number of events dropped since the last overflow
+// status.
+func (q *listenerQueue) droppedCount() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.dropped
+}
+
+// pending returns the number of queued deliveries.
+func (q *listenerQueue) pending() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return len(q.items)
+}
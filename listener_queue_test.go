@@ -0,0 +1,208 @@
+package pubnub
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockedQueue returns a queue whose delivery goroutine is stuck on the first
+// delivery until release is closed.
+func blockedQueue(capacity int, policy ListenerOverflowPolicy,
+	overflow func(int) listenerDelivery) (*listenerQueue, chan struct{}) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	q := newListenerQueue(capacity, policy, overflow)
+	q.push(func(done <-chan struct{}) {
+		close(started)
+		select {
+		case <-release:
+		case <-done:
+		}
+	})
+	<-started
+	return q, release
+}
+
+type deliveryRecorder struct {
+	sync.Mutex
+	delivered []int
+	notify    chan int
+}
+
+func newDeliveryRecorder() *deliveryRecorder {
+	return &deliveryRecorder{notify: make(chan int, 100)}
+}
+
+func (r *deliveryRecorder) delivery(i int) listenerDelivery {
+	return func(done <-chan struct{}) {
+		r.Lock()
+		r.delivered = append(r.delivered, i)
+		r.Unlock()
+		r.notify <- i
+	}
+}
+
+// wait waits for n deliveries and returns all the delivered values.
+func (r *deliveryRecorder) wait(t *testing.T, n int) []int {
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.notify:
+		case <-time.After(2 * time.Second):
+			assert.Fail(t, "delivery timed out")
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	return append([]int(nil), r.delivered...)
+}
+
+func TestListenerQueueOrder(t *testing.T) {
+	assert := assert.New(t)
+	r := newDeliveryRecorder()
+
+	q := newListenerQueue(10, PNListenerOverflowBlock, nil)
+	defer q.close()
+
+	expected := make([]int, 100)
+	for i := 0; i < 100; i++ {
+		expected[i] = i
+		q.push(r.delivery(i))
+	}
+
+	assert.Equal(expected, r.wait(t, 100))
+}
+
+func TestListenerQueueDropNewest(t *testing.T) {
+	assert := assert.New(t)
+	r := newDeliveryRecorder()
+
+	q, release := blockedQueue(2, PNListenerOverflowDropNewest, nil)
+	defer q.close()
+
+	assert.True(q.push(r.delivery(1)))
+	assert.True(q.push(r.delivery(2)))
+	assert.False(q.push(r.delivery(3)))
+	close(release)
+
+	assert.Equal([]int{1, 2}, r.wait(t, 2))
+}
+
+func TestListenerQueueDropOldest(t *testing.T) {
+	assert := assert.New(t)
+	r := newDeliveryRecorder()
+
+	q, release := blockedQueue(2, PNListenerOverflowDropOldest, nil)
+	defer q.close()
+
+	q.push(r.delivery(1))
+	q.push(r.delivery(2))
+	q.push(r.delivery(3))
+	close(release)
+
+	assert.Equal([]int{2, 3}, r.wait(t, 2))
+}
+
+func TestListenerQueueBlock(t *testing.T) {
+	assert := assert.New(t)
+	r := newDeliveryRecorder()
+
+	q, release := blockedQueue(1, PNListenerOverflowBlock, nil)
+	defer q.close()
+
+	q.push(r.delivery(1))
+
+	pushed := make(chan bool, 1)
+	go func() {
+		pushed <- q.push(r.delivery(2))
+	}()
+
+	select {
+	case <-pushed:
+		assert.Fail("push didn't block on a full queue")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case ok := <-pushed:
+		assert.True(ok)
+	case <-time.After(2 * time.Second):
+		assert.Fail("push wasn't released")
+	}
+	assert.Equal([]int{1, 2}, r.wait(t, 2))
+}
+
+func TestListenerQueueOverflowStatus(t *testing.T) {
+	assert := assert.New(t)
+	r := newDeliveryRecorder()
+
+	overflow := func(dropped int) listenerDelivery {
+		return r.delivery(-dropped)
+	}
+	q, release := blockedQueue(1, PNListenerOverflowStatus, overflow)
+	defer q.close()
+
+	q.push(r.delivery(1))
+	q.push(r.delivery(2))
+	q.push(r.delivery(3))
+	assert.Equal(2, q.droppedCount())
+	close(release)
+
+	// the queue is empty once 1 is delivered
+	assert.Equal([]int{1}, r.wait(t, 1))
+	q.push(r.delivery(4))
+
+	assert.Equal([]int{1, -2, 4}, r.wait(t, 2))
+	assert.Equal(0, q.droppedCount())
+}
+
+func TestListenerQueueClose(t *testing.T) {
+	assert := assert.New(t)
+
+	q := newListenerQueue(1, PNListenerOverflowBlock, nil)
+	started := make(chan struct{})
+	exited := make(chan struct{})
+	q.push(func(done <-chan struct{}) {
+		close(started)
+		<-done
+		close(exited)
+	})
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		assert.Fail("delivery didn't start")
+		return
+	}
+	q.close()
+
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		assert.Fail("delivery wasn't released on close")
+	}
+	assert.False(q.push(func(done <-chan struct{}) {}))
+}
+
+func TestListenerQueueCloseReleasesBlockedPush(t *testing.T) {
+	assert := assert.New(t)
+
+	q, _ := blockedQueue(1, PNListenerOverflowBlock, nil)
+	q.push(func(done <-chan struct{}) {})
+
+	pushed := make(chan bool, 1)
+	go func() {
+		pushed <- q.push(func(done <-chan struct{}) {})
+	}()
+	q.close()
+
+	select {
+	case ok := <-pushed:
+		assert.False(ok)
+	case <-time.After(2 * time.Second):
+		assert.Fail("push wasn't released on close")
+	}
+}
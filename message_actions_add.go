@@ -74,6 +74,13 @@ func (b *addMessageActionsBuilder) QueryParam(queryParam map[string]string) *add
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *addMessageActionsBuilder) RetryPolicy(policy RetryPolicy) *addMessageActionsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the addMessageActions request.
 func (b *addMessageActionsBuilder) Transport(tr http.RoundTripper) *addMessageActionsBuilder {
 	b.opts.Transport = tr
@@ -100,6 +107,8 @@ type addMessageActionsOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -184,6 +193,17 @@ func (o *addMessageActionsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *addMessageActionsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *addMessageActionsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNMessageActionsResponse Message Actions response.
 type PNMessageActionsResponse struct {
 	ActionType       string `json:"type"`
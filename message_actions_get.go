@@ -75,6 +75,13 @@ func (b *getMessageActionsBuilder) QueryParam(queryParam map[string]string) *get
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getMessageActionsBuilder) RetryPolicy(policy RetryPolicy) *getMessageActionsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getMessageActions request.
 func (b *getMessageActionsBuilder) Transport(tr http.RoundTripper) *getMessageActionsBuilder {
 	b.opts.Transport = tr
@@ -102,6 +109,8 @@ type getMessageActionsOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -191,6 +200,17 @@ func (o *getMessageActionsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getMessageActionsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getMessageActionsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetMessageActionsMore is the struct used when the PNGetMessageActionsResponse has more link
 type PNGetMessageActionsMore struct {
 	URL   string `json:"url"`
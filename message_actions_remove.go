@@ -68,6 +68,13 @@ func (b *removeMessageActionsBuilder) QueryParam(queryParam map[string]string) *
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeMessageActionsBuilder) RetryPolicy(policy RetryPolicy) *removeMessageActionsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the removeMessageActions request.
 func (b *removeMessageActionsBuilder) Transport(tr http.RoundTripper) *removeMessageActionsBuilder {
 	b.opts.Transport = tr
@@ -95,6 +102,8 @@ type removeMessageActionsOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -172,6 +181,17 @@ func (o *removeMessageActionsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeMessageActionsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeMessageActionsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRemoveMessageActionsResponse is the Objects API Response for create space
 type PNRemoveMessageActionsResponse struct {
 	status int         `json:"status"`
@@ -75,6 +75,13 @@ func (b *messageCountsBuilder) QueryParam(queryParam map[string]string) *message
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *messageCountsBuilder) RetryPolicy(policy RetryPolicy) *messageCountsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the MessageCounts request.
 func (b *messageCountsBuilder) Transport(tr http.RoundTripper) *messageCountsBuilder {
 	b.opts.Transport = tr
@@ -103,6 +110,8 @@ type messageCountsOpts struct {
 	// nil hacks
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -206,6 +215,17 @@ func (o *messageCountsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *messageCountsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *messageCountsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // MessageCountsResponse is the response to MessageCounts request. It contains a map of type MessageCountsResponseItem
 type MessageCountsResponse struct {
 	Channels map[string]int
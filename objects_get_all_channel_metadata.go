@@ -96,6 +96,13 @@ func (b *getAllChannelMetadataBuilder) QueryParam(queryParam map[string]string)
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getAllChannelMetadataBuilder) RetryPolicy(policy RetryPolicy) *getAllChannelMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getAllChannelMetadata request.
 func (b *getAllChannelMetadataBuilder) Transport(tr http.RoundTripper) *getAllChannelMetadataBuilder {
 	b.opts.Transport = tr
@@ -126,6 +133,8 @@ type getAllChannelMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -229,6 +238,17 @@ func (o *getAllChannelMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getAllChannelMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getAllChannelMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetAllChannelMetadataResponse is the Objects API Response for Get Spaces
 type PNGetAllChannelMetadataResponse struct {
 	status     int         `json:"status"`
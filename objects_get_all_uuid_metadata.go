@@ -96,6 +96,13 @@ func (b *getAllUUIDMetadataBuilder) QueryParam(queryParam map[string]string) *ge
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getAllUUIDMetadataBuilder) RetryPolicy(policy RetryPolicy) *getAllUUIDMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getAllUUIDMetadata request.
 func (b *getAllUUIDMetadataBuilder) Transport(tr http.RoundTripper) *getAllUUIDMetadataBuilder {
 	b.opts.Transport = tr
@@ -126,6 +133,8 @@ type getAllUUIDMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -229,6 +238,17 @@ func (o *getAllUUIDMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getAllUUIDMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getAllUUIDMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetAllUUIDMetadataResponse is the Objects API Response for Get Users
 type PNGetAllUUIDMetadataResponse struct {
 	status     int      `json:"status"`
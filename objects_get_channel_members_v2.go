@@ -102,6 +102,13 @@ func (b *getChannelMembersBuilderV2) QueryParam(queryParam map[string]string) *g
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getChannelMembersBuilderV2) RetryPolicy(policy RetryPolicy) *getChannelMembersBuilderV2 {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getChannelMembers request.
 func (b *getChannelMembersBuilderV2) Transport(tr http.RoundTripper) *getChannelMembersBuilderV2 {
 	b.opts.Transport = tr
@@ -132,6 +139,8 @@ type getChannelMembersOptsV2 struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -239,6 +248,17 @@ func (o *getChannelMembersOptsV2) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getChannelMembersOptsV2) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getChannelMembersOptsV2) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetChannelMembersResponse is the Objects API Response for Get Members
 type PNGetChannelMembersResponse struct {
 	status     int                `json:"status"`
@@ -61,6 +61,13 @@ func (b *getChannelMetadataBuilder) QueryParam(queryParam map[string]string) *ge
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getChannelMetadataBuilder) RetryPolicy(policy RetryPolicy) *getChannelMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getChannelMetadata request.
 func (b *getChannelMetadataBuilder) Transport(tr http.RoundTripper) *getChannelMetadataBuilder {
 	b.opts.Transport = tr
@@ -85,6 +92,8 @@ type getChannelMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -169,6 +178,17 @@ func (o *getChannelMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getChannelMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getChannelMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetChannelMetadataResponse is the Objects API Response for Get Space
 type PNGetChannelMetadataResponse struct {
 	status int       `json:"status"`
@@ -102,6 +102,13 @@ func (b *getMembershipsBuilderV2) QueryParam(queryParam map[string]string) *getM
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getMembershipsBuilderV2) RetryPolicy(policy RetryPolicy) *getMembershipsBuilderV2 {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getMemberships request.
 func (b *getMembershipsBuilderV2) Transport(tr http.RoundTripper) *getMembershipsBuilderV2 {
 	b.opts.Transport = tr
@@ -136,6 +143,8 @@ type getMembershipsOptsV2 struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -239,6 +248,17 @@ func (o *getMembershipsOptsV2) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getMembershipsOptsV2) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getMembershipsOptsV2) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetMembershipsResponse is the Objects API Response for Get Memberships
 type PNGetMembershipsResponse struct {
 	status     int             `json:"status"`
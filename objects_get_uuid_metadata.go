@@ -61,6 +61,13 @@ func (b *getUUIDMetadataBuilder) QueryParam(queryParam map[string]string) *getUU
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *getUUIDMetadataBuilder) RetryPolicy(policy RetryPolicy) *getUUIDMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the getUUIDMetadata request.
 func (b *getUUIDMetadataBuilder) Transport(tr http.RoundTripper) *getUUIDMetadataBuilder {
 	b.opts.Transport = tr
@@ -89,6 +96,8 @@ type getUUIDMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -170,6 +179,17 @@ func (o *getUUIDMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *getUUIDMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *getUUIDMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNGetUUIDMetadataResponse is the Objects API Response for Get User
 type PNGetUUIDMetadataResponse struct {
 	status int    `json:"status"`
@@ -114,6 +114,13 @@ func (b *manageChannelMembersBuilderV2) QueryParam(queryParam map[string]string)
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *manageChannelMembersBuilderV2) RetryPolicy(policy RetryPolicy) *manageChannelMembersBuilderV2 {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the manageMembers request.
 func (b *manageChannelMembersBuilderV2) Transport(tr http.RoundTripper) *manageChannelMembersBuilderV2 {
 	b.opts.Transport = tr
@@ -145,6 +152,8 @@ type manageMembersOptsV2 struct {
 	MembersSet    []PNChannelMembersSet
 	Transport     http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -264,6 +273,17 @@ func (o *manageMembersOptsV2) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *manageMembersOptsV2) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *manageMembersOptsV2) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNManageMembersResponse is the Objects API Response for ManageMembers
 type PNManageMembersResponse struct {
 	status     int                `json:"status"`
@@ -114,6 +114,13 @@ func (b *manageMembershipsBuilderV2) QueryParam(queryParam map[string]string) *m
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *manageMembershipsBuilderV2) RetryPolicy(policy RetryPolicy) *manageMembershipsBuilderV2 {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the manageMemberships request.
 func (b *manageMembershipsBuilderV2) Transport(tr http.RoundTripper) *manageMembershipsBuilderV2 {
 	b.opts.Transport = tr
@@ -149,6 +156,8 @@ type manageMembershipsOptsV2 struct {
 	MembershipsSet    []PNMembershipsSet
 	Transport         http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -263,6 +272,17 @@ func (o *manageMembershipsOptsV2) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *manageMembershipsOptsV2) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *manageMembershipsOptsV2) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNManageMembershipsResponse is the Objects API Response for ManageMemberships
 type PNManageMembershipsResponse struct {
 	status     int             `json:"status"`
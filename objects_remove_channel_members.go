@@ -108,6 +108,13 @@ func (b *removeChannelMembersBuilder) QueryParam(queryParam map[string]string) *
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeChannelMembersBuilder) RetryPolicy(policy RetryPolicy) *removeChannelMembersBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the removeChannelMembers request.
 func (b *removeChannelMembersBuilder) Transport(tr http.RoundTripper) *removeChannelMembersBuilder {
 	b.opts.Transport = tr
@@ -138,6 +145,8 @@ type removeChannelMembersOpts struct {
 	ChannelMembersRemove []PNChannelMembersRemove
 	Transport            http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -259,6 +268,17 @@ func (o *removeChannelMembersOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeChannelMembersOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeChannelMembersOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRemoveChannelMembersResponse is the Objects API Response for RemoveChannelMembers
 type PNRemoveChannelMembersResponse struct {
 	status     int                `json:"status"`
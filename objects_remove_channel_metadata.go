@@ -56,6 +56,13 @@ func (b *removeChannelMetadataBuilder) QueryParam(queryParam map[string]string)
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeChannelMetadataBuilder) RetryPolicy(policy RetryPolicy) *removeChannelMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the removeChannelMetadata request.
 func (b *removeChannelMetadataBuilder) Transport(tr http.RoundTripper) *removeChannelMetadataBuilder {
 	b.opts.Transport = tr
@@ -78,6 +85,8 @@ type removeChannelMetadataOpts struct {
 	QueryParam map[string]string
 	Transport  http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -158,6 +167,17 @@ func (o *removeChannelMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeChannelMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeChannelMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRemoveChannelMetadataResponse is the Objects API Response for delete space
 type PNRemoveChannelMetadataResponse struct {
 	status int         `json:"status"`
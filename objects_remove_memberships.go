@@ -108,6 +108,13 @@ func (b *removeMembershipsBuilder) QueryParam(queryParam map[string]string) *rem
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeMembershipsBuilder) RetryPolicy(policy RetryPolicy) *removeMembershipsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the removeMemberships request.
 func (b *removeMembershipsBuilder) Transport(tr http.RoundTripper) *removeMembershipsBuilder {
 	b.opts.Transport = tr
@@ -142,6 +149,8 @@ type removeMembershipsOpts struct {
 	MembershipsRemove []PNMembershipsRemove
 	Transport         http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -262,6 +271,17 @@ func (o *removeMembershipsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeMembershipsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeMembershipsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRemoveMembershipsResponse is the Objects API Response for RemoveMemberships
 type PNRemoveMembershipsResponse struct {
 	status     int             `json:"status"`
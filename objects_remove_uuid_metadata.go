@@ -56,6 +56,13 @@ func (b *removeUUIDMetadataBuilder) QueryParam(queryParam map[string]string) *re
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeUUIDMetadataBuilder) RetryPolicy(policy RetryPolicy) *removeUUIDMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the removeUUIDMetadata request.
 func (b *removeUUIDMetadataBuilder) Transport(tr http.RoundTripper) *removeUUIDMetadataBuilder {
 	b.opts.Transport = tr
@@ -83,6 +90,8 @@ type removeUUIDMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -160,6 +169,17 @@ func (o *removeUUIDMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeUUIDMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeUUIDMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRemoveUUIDMetadataResponse is the Objects API Response for delete user
 type PNRemoveUUIDMetadataResponse struct {
 	status int         `json:"status"`
@@ -108,6 +108,13 @@ func (b *setChannelMembersBuilder) QueryParam(queryParam map[string]string) *set
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *setChannelMembersBuilder) RetryPolicy(policy RetryPolicy) *setChannelMembersBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the setChannelMembers request.
 func (b *setChannelMembersBuilder) Transport(tr http.RoundTripper) *setChannelMembersBuilder {
 	b.opts.Transport = tr
@@ -138,6 +145,8 @@ type setChannelMembersOpts struct {
 	ChannelMembersSet []PNChannelMembersSet
 	Transport         http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -259,6 +268,17 @@ func (o *setChannelMembersOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *setChannelMembersOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *setChannelMembersOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSetChannelMembersResponse is the Objects API Response for SetChannelMembers
 type PNSetChannelMembersResponse struct {
 	status     int                `json:"status"`
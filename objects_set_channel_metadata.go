@@ -87,6 +87,13 @@ func (b *setChannelMetadataBuilder) QueryParam(queryParam map[string]string) *se
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *setChannelMetadataBuilder) RetryPolicy(policy RetryPolicy) *setChannelMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the setChannelMetadata request.
 func (b *setChannelMetadataBuilder) Transport(tr http.RoundTripper) *setChannelMetadataBuilder {
 	b.opts.Transport = tr
@@ -114,6 +121,8 @@ type setChannelMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -211,6 +220,17 @@ func (o *setChannelMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *setChannelMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *setChannelMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSetChannelMetadataResponse is the Objects API Response for Update Space
 type PNSetChannelMetadataResponse struct {
 	status int       `json:"status"`
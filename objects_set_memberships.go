@@ -108,6 +108,13 @@ func (b *setMembershipsBuilder) QueryParam(queryParam map[string]string) *setMem
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *setMembershipsBuilder) RetryPolicy(policy RetryPolicy) *setMembershipsBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the setMemberships request.
 func (b *setMembershipsBuilder) Transport(tr http.RoundTripper) *setMembershipsBuilder {
 	b.opts.Transport = tr
@@ -142,6 +149,8 @@ type setMembershipsOpts struct {
 	MembershipsSet []PNMembershipsSet
 	Transport      http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -262,6 +271,17 @@ func (o *setMembershipsOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *setMembershipsOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *setMembershipsOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSetMembershipsResponse is the Objects API Response for SetMemberships
 type PNSetMembershipsResponse struct {
 	status     int             `json:"status"`
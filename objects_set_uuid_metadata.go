@@ -101,6 +101,13 @@ func (b *setUUIDMetadataBuilder) QueryParam(queryParam map[string]string) *setUU
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *setUUIDMetadataBuilder) RetryPolicy(policy RetryPolicy) *setUUIDMetadataBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Transport sets the Transport for the setUUIDMetadata request.
 func (b *setUUIDMetadataBuilder) Transport(tr http.RoundTripper) *setUUIDMetadataBuilder {
 	b.opts.Transport = tr
@@ -134,6 +141,8 @@ type setUUIDMetadataOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -230,6 +239,17 @@ func (o *setUUIDMetadataOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *setUUIDMetadataOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *setUUIDMetadataOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNSetUUIDMetadataResponse is the Objects API Response for Update user
 type PNSetUUIDMetadataResponse struct {
 	status int    `json:"status"`
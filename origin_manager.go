@@ -0,0 +1,113 @@
+package pubnub
+
+import (
+	"sync"
+	"time"
+)
+
+// OriginHealthPolicy configures the failover between Config.Origins.
+type OriginHealthPolicy struct {
+	// FailureThreshold is the number of consecutive connection failures after
+	// which the next origin is used, 0 means 1.
+	FailureThreshold int
+	// PrimaryRetryInterval is the number of seconds after a failover the
+	// first origin is used again, 0 keeps the healthy origin until it fails.
+	PrimaryRetryInterval int
+}
+
+// OriginManager picks the origin of the requests among Config.Origins and
+// rotates to the next one after connection failures.
+type OriginManager struct {
+	sync.RWMutex
+
+	pubnub       *PubNub
+	current      int
+	failures     int
+	failedOverAt time.Time
+}
+
+func newOriginManager(pubnub *PubNub) *OriginManager {
+	return &OriginManager{
+		pubnub: pubnub,
+	}
+}
+
+func (m *OriginManager) origins() []string {
+	m.pubnub.Config.RLock()
+	defer m.pubnub.Config.RUnlock()
+
+	if len(m.pubnub.Config.Origins) > 0 {
+		return m.pubnub.Config.Origins
+	}
+	return []string{m.pubnub.Config.Origin}
+}
+
+func (m *OriginManager) policy() OriginHealthPolicy {
+	m.pubnub.Config.RLock()
+	defer m.pubnub.Config.RUnlock()
+
+	return m.pubnub.Config.OriginHealthPolicy
+}
+
+// canFailover reports whether there is another origin to rotate to.
+func (m *OriginManager) canFailover() bool {
+	return len(m.origins()) > 1
+}
+
+// currentOrigin returns the origin the requests are sent to.
+func (m *OriginManager) currentOrigin() string {
+	origins := m.origins()
+	interval := time.Duration(m.policy().PrimaryRetryInterval) * time.Second
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.current >= len(origins) {
+		// Config.Origins was changed
+		m.current = 0
+	}
+	if m.current != 0 && interval > 0 && time.Since(m.failedOverAt) >= interval {
+		m.pubnub.Config.Log.Println("origin: retrying", origins[0])
+		m.current = 0
+		m.failures = 0
+	}
+	return origins[m.current]
+}
+
+// reportSuccess resets the failures of origin once a response was received.
+func (m *OriginManager) reportSuccess(origin string) {
+	origins := m.origins()
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.current < len(origins) && origins[m.current] == origin {
+		m.failures = 0
+	}
+}
+
+// reportFailure records a connection failure of origin and rotates to the
+// next origin once the failure threshold is reached. The failures of an
+// origin already rotated away from are ignored.
+func (m *OriginManager) reportFailure(origin string) {
+	origins := m.origins()
+	threshold := m.policy().FailureThreshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if len(origins) < 2 || m.current >= len(origins) || origins[m.current] != origin {
+		return
+	}
+	m.failures++
+	if m.failures < threshold {
+		return
+	}
+	m.current = (m.current + 1) % len(origins)
+	m.failures = 0
+	m.failedOverAt = time.Now()
+	m.pubnub.Config.Log.Println("origin: failing over from", origin, "to", origins[m.current])
+}
@@ -0,0 +1,225 @@
+package pubnub
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginManagerSingleOrigin(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	m := pn.originManager
+
+	assert.Equal("ps.pndsn.com", m.currentOrigin())
+	assert.False(m.canFailover())
+	m.reportFailure("ps.pndsn.com")
+	assert.Equal("ps.pndsn.com", m.currentOrigin())
+
+	pn.Config.Origin = "custom.example.com"
+	assert.Equal("custom.example.com", m.currentOrigin())
+}
+
+func TestOriginManagerRotation(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a", "b", "c"}
+	config.OriginHealthPolicy.FailureThreshold = 2
+	pn := NewPubNub(config)
+	m := pn.originManager
+
+	assert.Equal("a", m.currentOrigin())
+	m.reportFailure("a")
+	m.reportSuccess("a")
+	m.reportFailure("a")
+	assert.Equal("a", m.currentOrigin())
+
+	m.reportFailure("a")
+	assert.Equal("b", m.currentOrigin())
+
+	// late failures of a request sent to the previous origin
+	m.reportFailure("a")
+	m.reportFailure("a")
+	assert.Equal("b", m.currentOrigin())
+
+	m.reportFailure("b")
+	m.reportFailure("b")
+	m.reportFailure("c")
+	m.reportFailure("c")
+	assert.Equal("a", m.currentOrigin())
+}
+
+func TestOriginManagerPrimaryRetryInterval(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a", "b"}
+	config.OriginHealthPolicy.PrimaryRetryInterval = 60
+	pn := NewPubNub(config)
+	m := pn.originManager
+
+	m.reportFailure("a")
+	assert.Equal("b", m.currentOrigin())
+
+	m.Lock()
+	m.failedOverAt = time.Now().Add(-time.Minute)
+	m.Unlock()
+	assert.Equal("a", m.currentOrigin())
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestExecuteRequestOriginFailover(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/time/0",
+		Query:              "",
+		ResponseBody:       `[15000000000000000]`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature", "l_time"},
+		ResponseStatusCode: 200,
+	})
+
+	config := NewDemoConfig()
+	config.Origins = []string{"down.example.com", "up.example.com"}
+	pn := NewPubNub(config)
+	pn.SetClient(&http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "down.example.com" {
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+			}
+			return interceptor.Transport.RoundTrip(req)
+		}),
+	})
+
+	_, status, err := pn.Time().Execute()
+	assert.NotNil(err)
+	assert.Equal("down.example.com", status.Origin)
+
+	res, status, err := pn.Time().Execute()
+	assert.Nil(err)
+	assert.Equal("up.example.com", status.Origin)
+	assert.Equal(int64(15000000000000000), res.Timetoken)
+
+	// sticks to the healthy origin
+	_, status, err = pn.Time().Execute()
+	assert.Nil(err)
+	assert.Equal("up.example.com", status.Origin)
+}
+
+func TestExecuteRequestServerErrorOrigin(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a.example.com", "b.example.com"}
+	pn := NewPubNub(config)
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, `{"error":true}`, 500))
+
+	_, status, err := pn.Time().Execute()
+	assert.NotNil(err)
+	assert.Equal(500, status.StatusCode)
+	assert.Equal("a.example.com", status.Origin)
+}
+
+func TestDownloadFileOriginFailover(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"down.example.com", "up.example.com"}
+	pn := NewPubNub(config)
+	pn.SetClient(&http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "down.example.com" {
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader("content")),
+				Request:    req,
+			}, nil
+		}),
+	})
+
+	_, status, err := pn.DownloadFile().Channel("ch").ID("id").Name("name").Execute()
+	assert.NotNil(err)
+	assert.Equal("down.example.com", status.Origin)
+
+	resp, status, err := pn.DownloadFile().Channel("ch").ID("id").Name("name").Execute()
+	assert.Nil(err)
+	assert.Equal("up.example.com", status.Origin)
+	content, err := ioutil.ReadAll(resp.File)
+	assert.Nil(err)
+	assert.Equal("content", string(content))
+}
+
+func TestClassifySubscribeErrorOriginFailover(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a", "b"}
+	config.PNReconnectionPolicy = PNExponentialPolicy
+	config.MaximumReconnectionRetries = 2
+	pn := NewPubNub(config)
+
+	connErr := pnerr.NewConnectionError("Failed to execute request",
+		&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+
+	action := pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.Equal(PNSubscribeStateReconnecting, action.state)
+	assert.True(action.retry)
+	assert.Equal(time.Second, action.delay)
+
+	action = pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.True(action.retry)
+	assert.Equal(3*time.Second, action.delay)
+
+	action = pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.Equal(PNSubscribeStateFailed, action.state)
+	assert.False(action.retry)
+
+	// a subscribe response resets the attempts
+	pn.subscriptionManager.reconnectionManager.resetFailover()
+	action = pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.True(action.retry)
+	assert.Equal(time.Second, action.delay)
+}
+
+func TestClassifySubscribeErrorOriginFailoverNonePolicy(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a", "b"}
+	pn := NewPubNub(config)
+
+	connErr := pnerr.NewConnectionError("Failed to execute request",
+		&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+
+	action := pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.Equal(PNSubscribeStateFailed, action.state)
+	assert.False(action.retry)
+}
+
+func TestHandleSubscribeErrorNonRetryableOriginFailover(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Origins = []string{"a", "b"}
+	config.PNReconnectionPolicy = PNLinearPolicy
+	pn := NewPubNub(config)
+	m := pn.subscriptionManager
+
+	connErr := pnerr.NewConnectionError("Failed to execute request",
+		&net.DNSError{Err: "no such host", Name: "a", IsNotFound: true})
+
+	generation := m.stateMachine.startLoop("subscribe loop started")
+	assert.False(m.handleSubscribeError(generation, backgroundContext, connErr))
+	assert.Equal(PNSubscribeStateFailed, m.stateMachine.currentState())
+}
@@ -0,0 +1,19 @@
+//go:build go1.21
+// +build go1.21
+
+package pnerr
+
+import (
+	"crypto/tls"
+	"errors"
+)
+
+// isTLSAlertError matches the alert and verification errors exposed by
+// crypto/tls since go1.21.
+func isTLSAlertError(err error) bool {
+	var (
+		alertErr  tls.AlertError
+		verifyErr *tls.CertificateVerificationError
+	)
+	return errors.As(err, &alertErr) || errors.As(err, &verifyErr)
+}
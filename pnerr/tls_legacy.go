@@ -0,0 +1,10 @@
+//go:build !go1.21
+// +build !go1.21
+
+package pnerr
+
+// isTLSAlertError is a no-op before go1.21, alerts are recognised by the
+// "remote error"/"local error" net.OpError in IsTLSError.
+func isTLSAlertError(err error) bool {
+	return false
+}
@@ -1,11 +1,35 @@
 package pnerr
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
+	"strings"
+	"time"
 )
 
+// RetryableError is implemented by all the errors of this package. Retryable
+// reports whether repeating the same request may succeed.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// IsRetryable reports whether any error in err's chain is a RetryableError
+// which can be retried.
+func IsRetryable(err error) bool {
+	var r RetryableError
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return false
+}
+
 // Error validating type or value of passed in params.
 // For ex. Channel Missing, Subscribe Key Missing, etc.
 // In most cases this happens due an incorrect SDK usage.
@@ -19,6 +43,11 @@ func (e ValidationError) OrigErr() error {
 	return nil
 }
 
+// Retryable returns false, the request has to be fixed before retrying.
+func (e ValidationError) Retryable() bool {
+	return false
+}
+
 func (e ValidationError) Error() string {
 	return fmt.Sprintf("pubnub/validation: %s", e.message)
 }
@@ -29,6 +58,34 @@ func NewValidationError(endpoint, msg string) *ValidationError {
 	}
 }
 
+// PayloadTooLargeError is the validation error of a message larger than
+// the service accepts, it isn't sent.
+type PayloadTooLargeError struct {
+	Endpoint string
+	// Size is the number of bytes of the message as sent, serialized and
+	// encrypted, with the channel name.
+	Size    int
+	MaxSize int
+}
+
+func (e PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("pubnub/validation: pubnub: %s: Message too large: %d bytes, the maximum is %d",
+		e.Endpoint, e.Size, e.MaxSize)
+}
+
+// Retryable returns false, the message has to be made smaller.
+func (e PayloadTooLargeError) Retryable() bool {
+	return false
+}
+
+func NewPayloadTooLargeError(endpoint string, size, maxSize int) *PayloadTooLargeError {
+	return &PayloadTooLargeError{
+		Endpoint: endpoint,
+		Size:     size,
+		MaxSize:  maxSize,
+	}
+}
+
 // Error building request with wrong params
 type BuildRequestError struct {
 	message string
@@ -38,6 +95,11 @@ func (e BuildRequestError) OrigErr() error {
 	return nil
 }
 
+// Retryable returns false, the request has to be fixed before retrying.
+func (e BuildRequestError) Retryable() bool {
+	return false
+}
+
 func (e BuildRequestError) Error() string {
 	return fmt.Sprintf("pubnub/build: %s", e.message)
 }
@@ -54,6 +116,9 @@ func NewBuildRequestError(msg string) *BuildRequestError {
 type ServerError struct {
 	StatusCode int
 	Body       []byte
+	// RetryAfter is the delay asked by the Retry-After header of the
+	// response, 0 when absent.
+	RetryAfter time.Duration
 }
 
 func (e ServerError) Error() string {
@@ -62,6 +127,27 @@ func (e ServerError) Error() string {
 		string(e.Body))
 }
 
+// Is makes errors.Is match a target ServerError with the same StatusCode. A
+// target with a zero StatusCode matches any ServerError, for ex.:
+// errors.Is(err, &pnerr.ServerError{StatusCode: 429})
+func (e ServerError) Is(target error) bool {
+	t, ok := target.(*ServerError)
+	if !ok {
+		return false
+	}
+	return t.StatusCode == 0 || t.StatusCode == e.StatusCode
+}
+
+// Retryable returns true for request timeouts (408), rate limiting (429) and
+// server side failures (5xx). 530 is returned when no stub matched the request
+// and retrying it won't change the outcome.
+func (e ServerError) Retryable() bool {
+	if e.StatusCode == 530 {
+		return false
+	}
+	return e.StatusCode == 408 || e.StatusCode == 429 || e.StatusCode >= 500
+}
+
 func NewServerError(statusCode int, body io.ReadCloser) *ServerError {
 	bodyString, _ := ioutil.ReadAll(body)
 
@@ -71,6 +157,30 @@ func NewServerError(statusCode int, body io.ReadCloser) *ServerError {
 	}
 }
 
+// RateLimitError is returned when a publish exceeds the client-side rate
+// limit and the rate limiter fails fast.
+type RateLimitError struct {
+	Channel string
+	// Wait is the time until the publish would be allowed.
+	Wait time.Duration
+}
+
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("pubnub/ratelimit: publish rate to %s exceeded, retry in %s", e.Channel, e.Wait)
+}
+
+// Retryable returns true, the publish is allowed once Wait elapsed.
+func (e RateLimitError) Retryable() bool {
+	return true
+}
+
+func NewRateLimitError(channel string, wait time.Duration) *RateLimitError {
+	return &RateLimitError{
+		Channel: channel,
+		Wait:    wait,
+	}
+}
+
 // Something wrong with network connection.
 type ConnectionError struct {
 	message   string
@@ -82,6 +192,108 @@ func (e ConnectionError) Error() string {
 		e.OrigError.Error())
 }
 
+// Unwrap returns the original network error.
+func (e ConnectionError) Unwrap() error {
+	return e.OrigError
+}
+
+// Retryable returns false if the request was cancelled, failed due to TLS
+// issues or certificate pinning, or the host doesn't exist, true otherwise.
+func (e ConnectionError) Retryable() bool {
+	if errors.Is(e.OrigError, context.Canceled) || IsTLSError(e.OrigError) {
+		return false
+	}
+	var pinErr *PinningError
+	if errors.As(e.OrigError, &pinErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(e.OrigError, &dnsErr) && isHostNotFound(dnsErr) {
+		return false
+	}
+	return true
+}
+
+// isHostNotFound reports whether the lookup failed permanently because the
+// host doesn't exist. Not every resolver sets IsNotFound, so the error text is
+// checked as well.
+func isHostNotFound(err *net.DNSError) bool {
+	if err.IsTimeout || err.IsTemporary {
+		return false
+	}
+	return err.IsNotFound || strings.Contains(err.Err, "no such host")
+}
+
+// IsTimeout reports whether the connection failed due to a timeout.
+func (e ConnectionError) IsTimeout() bool {
+	if errors.Is(e.OrigError, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.OrigError, &netErr) && netErr.Timeout()
+}
+
+// IsDNSError reports whether the host lookup of the connection failed.
+func (e ConnectionError) IsDNSError() bool {
+	var dnsErr *net.DNSError
+	return errors.As(e.OrigError, &dnsErr)
+}
+
+// IsTLSError reports whether err is caused by a failed TLS handshake or
+// certificate verification.
+func IsTLSError(err error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalidCert      x509.CertificateInvalidError
+		hostname         x509.HostnameError
+		systemRoots      x509.SystemRootsError
+		recordHeader     tls.RecordHeaderError
+	)
+	if errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalidCert) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &systemRoots) ||
+		errors.As(err, &recordHeader) ||
+		isTLSAlertError(err) {
+		return true
+	}
+
+	// Alerts sent or received during the handshake are reported as
+	// "remote error: tls: ..." / "local error: tls: ..." net.OpErrors wrapping
+	// an unexported type.
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil &&
+		(opErr.Op == "remote error" || opErr.Op == "local error") {
+		return strings.HasPrefix(opErr.Err.Error(), "tls: ")
+	}
+	return false
+}
+
+// PinningError is returned when no certificate presented by the server
+// matches the pinned public keys.
+type PinningError struct {
+	Host string
+	// Pins are the SPKI pins of the certificates presented by the server.
+	Pins []string
+}
+
+func (e PinningError) Error() string {
+	return fmt.Sprintf("pubnub/pinning: no pinned public key matches the certificates of %s: %s",
+		e.Host, strings.Join(e.Pins, ", "))
+}
+
+// Retryable returns false, the server will present the same certificates.
+func (e PinningError) Retryable() bool {
+	return false
+}
+
+func NewPinningError(host string, pins []string) *PinningError {
+	return &PinningError{
+		Host: host,
+		Pins: pins,
+	}
+}
+
 func NewConnectionError(msg string, origError error) *ConnectionError {
 	return &ConnectionError{
 		message:   msg,
@@ -100,6 +312,16 @@ func (e ResponseParsingError) Error() string {
 	return fmt.Sprintf("pubnub/parsing: %s: %s", e.message, e.Body)
 }
 
+// Unwrap returns the original decoding error.
+func (e ResponseParsingError) Unwrap() error {
+	return e.OrigError
+}
+
+// Retryable returns false, the same response will fail to parse again.
+func (e ResponseParsingError) Retryable() bool {
+	return false
+}
+
 func NewResponseParsingError(msg string,
 	body io.ReadCloser, origError error) *ResponseParsingError {
 
@@ -0,0 +1,57 @@
+package pubnub
+
+// restoreState sets the state of the subscribed channels and channel groups
+// again after the server may have lost it, see Config.RestorePresenceState.
+// The result of every Set State request is announced as a status.
+func (m *SubscriptionManager) restoreState(reason string) {
+	if !m.pubnub.Config.RestorePresenceState {
+		return
+	}
+
+	for _, op := range m.stateManager.prepareStateOperations() {
+		m.pubnub.Config.Log.Println("restoring presence state,", reason, op.channels, op.channelGroups)
+		_, status, err := m.pubnub.SetState().Channels(op.channels).
+			ChannelGroups(op.channelGroups).State(op.state).Execute()
+
+		pnStatus := &PNStatus{
+			Category:              PNAcknowledgmentCategory,
+			Operation:             PNSetStateOperation,
+			StatusCode:            status.StatusCode,
+			UUID:                  m.pubnub.Config.UUID,
+			AffectedChannels:      op.channels,
+			AffectedChannelGroups: op.channelGroups,
+		}
+		if err != nil {
+			pnStatus.Category = categoryForError(err)
+			pnStatus.Error = true
+			pnStatus.ErrorData = err
+			m.pubnub.Config.Log.Println("restoring presence state: err", err)
+		}
+		m.listenerManager.announceStatus(pnStatus)
+	}
+}
+
+// checkStateUUID restores the state when Config.UUID changed since the
+// previous subscribe request, the state is kept per UUID by the server.
+func (m *SubscriptionManager) checkStateUUID() {
+	uuid := m.pubnub.Config.UUID
+
+	m.Lock()
+	previous := m.stateUUID
+	m.stateUUID = uuid
+	m.Unlock()
+
+	if previous != "" && previous != uuid {
+		go m.restoreState("uuid changed")
+	}
+}
+
+// isOwnTimeout reports whether a presence event is the timeout of the UUID
+// of this client.
+func (m *SubscriptionManager) isOwnTimeout(presence *PNPresence) bool {
+	uuid := m.pubnub.Config.UUID
+	if presence.Event == "timeout" && presence.UUID == uuid {
+		return true
+	}
+	return hasString(presence.Timeout, uuid)
+}
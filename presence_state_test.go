@@ -0,0 +1,129 @@
+package pubnub
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSetStateStub(interceptor *stubs.Interceptor, channel, uuid, state string) {
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/presence/sub-key/demo/channel/" + channel + "/uuid/" + uuid + "/data",
+		Query:              "state=" + url.QueryEscape(state),
+		ResponseBody:       `{"status": 200, "message": "OK", "payload": ` + state + `, "service": "Presence"}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+}
+
+func waitSetStateStatus(t *testing.T, listener *Listener) *PNStatus {
+	for {
+		select {
+		case status := <-listener.Status:
+			if status.Operation == PNSetStateOperation {
+				return status
+			}
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "set state status not announced")
+			return &PNStatus{}
+		}
+	}
+}
+
+func TestRestoreStateAfterOwnTimeout(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	newSetStateStub(interceptor, "a", "tester", `{"k":1}`)
+
+	config := NewDemoConfig()
+	config.UUID = "tester"
+	config.RestorePresenceState = true
+	pn := NewPubNub(config)
+	pn.SetClient(interceptor.GetClient())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	m := pn.subscriptionManager
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"a"},
+		State:    map[string]interface{}{"k": 1},
+	})
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{
+		ChannelGroups: []string{"cg"},
+		State:         map[string]interface{}{"k": 2},
+	})
+
+	// someone else timing out doesn't matter
+	processSubscribePayload(m, presencePayload("timeout", map[string]interface{}{"uuid": "other"}))
+	processSubscribePayload(m, presencePayload("timeout", map[string]interface{}{"uuid": "tester"}))
+
+	status := waitSetStateStatus(t, listener)
+	assert.False(status.Error)
+	assert.Equal(PNAcknowledgmentCategory, status.Category)
+	assert.Equal([]string{"a"}, status.AffectedChannels)
+
+	// not stubbed
+	status = waitSetStateStatus(t, listener)
+	assert.True(status.Error)
+	assert.Equal([]string{"cg"}, status.AffectedChannelGroups)
+
+	select {
+	case status := <-listener.Status:
+		assert.Fail("unexpected status", "%v", status)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestRestoreStateAfterUUIDChange(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	newSetStateStub(interceptor, "a", "renamed", `{"k":1}`)
+
+	config := NewDemoConfig()
+	config.UUID = "tester"
+	config.RestorePresenceState = true
+	pn := NewPubNub(config)
+	pn.SetClient(interceptor.GetClient())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	m := pn.subscriptionManager
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"a"},
+		State:    map[string]interface{}{"k": 1},
+	})
+
+	m.checkStateUUID()
+	m.checkStateUUID()
+	pn.Config.UUID = "renamed"
+	m.checkStateUUID()
+
+	status := waitSetStateStatus(t, listener)
+	assert.False(status.Error)
+	assert.Equal("renamed", status.UUID)
+	assert.Equal([]string{"a"}, status.AffectedChannels)
+}
+
+func TestRestoreStateDisabled(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	m := pn.subscriptionManager
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"a"},
+		State:    map[string]interface{}{"k": 1},
+	})
+	m.restoreState("test")
+
+	select {
+	case status := <-listener.Status:
+		assert.Fail("unexpected status", "%v", status)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
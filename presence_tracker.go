@@ -0,0 +1,269 @@
+package pubnub
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// PNPresenceChange is a change of the roster of a PresenceTracker.
+type PNPresenceChange struct {
+	Channel   string
+	UUID      string
+	Event     string // "join", "leave", "timeout" or "state-change".
+	State     map[string]interface{}
+	Occupancy int // Occupancy of Channel after the change.
+}
+
+// PresenceTracker maintains the members, their state and the occupancy of
+// channels from the presence events. It's seeded with HereNow and refreshed
+// with HereNow when an interval event asks for it or after a reconnection.
+// The channels have to be subscribed with presence for the roster to be
+// updated, the tracker doesn't subscribe by itself.
+type PresenceTracker struct {
+	sync.RWMutex
+
+	// OnChange is called for every change of the roster, it has to be set
+	// before Start. It's called from the delivery goroutine of the tracker.
+	OnChange func(change *PNPresenceChange)
+
+	pubnub   *PubNub
+	channels []string
+	rosters  map[string]*presenceRoster
+	listener *EventListener
+}
+
+type presenceRoster struct {
+	occupancy int
+	members   map[string]map[string]interface{}
+}
+
+func newPresenceTracker(pubnub *PubNub, channels []string) *PresenceTracker {
+	t := &PresenceTracker{
+		pubnub:   pubnub,
+		channels: channels,
+		rosters:  make(map[string]*presenceRoster),
+	}
+	for _, ch := range channels {
+		t.rosters[ch] = &presenceRoster{members: make(map[string]map[string]interface{})}
+	}
+	t.listener = &EventListener{
+		OnPresence: t.onPresence,
+		OnStatus:   t.onStatus,
+	}
+	return t
+}
+
+// Start starts tracking the presence events and seeds the rosters with
+// HereNow. The events are tracked even if the seeding failed.
+func (t *PresenceTracker) Start() error {
+	t.pubnub.AddEventListener(t.listener)
+	return t.refresh(t.channels)
+}
+
+// Stop stops tracking the presence events, the rosters are kept as is.
+func (t *PresenceTracker) Stop() {
+	t.pubnub.RemoveEventListener(t.listener)
+}
+
+// Channels returns the tracked channels.
+func (t *PresenceTracker) Channels() []string {
+	return append([]string{}, t.channels...)
+}
+
+// Occupancy returns the occupancy of channel.
+func (t *PresenceTracker) Occupancy(channel string) int {
+	t.RLock()
+	defer t.RUnlock()
+
+	if roster, ok := t.rosters[channel]; ok {
+		return roster.occupancy
+	}
+	return 0
+}
+
+// Members returns the sorted UUIDs present on channel.
+func (t *PresenceTracker) Members(channel string) []string {
+	t.RLock()
+	defer t.RUnlock()
+
+	roster, ok := t.rosters[channel]
+	if !ok {
+		return nil
+	}
+	members := make([]string, 0, len(roster.members))
+	for uuid := range roster.members {
+		members = append(members, uuid)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// State returns the state of uuid on channel, or nil if it isn't present.
+func (t *PresenceTracker) State(channel, uuid string) map[string]interface{} {
+	t.RLock()
+	defer t.RUnlock()
+
+	roster, ok := t.rosters[channel]
+	if !ok {
+		return nil
+	}
+	return copyState(roster.members[uuid])
+}
+
+func (t *PresenceTracker) onPresence(presence *PNPresence) {
+	t.Lock()
+	roster, ok := t.rosters[presence.Channel]
+	if !ok {
+		t.Unlock()
+		return
+	}
+
+	var changes []*PNPresenceChange
+	state, _ := presence.State.(map[string]interface{})
+	switch presence.Event {
+	case "join":
+		changes = roster.join(changes, presence.Channel, presence.UUID, state)
+	case "leave", "timeout":
+		changes = roster.leave(changes, presence.Channel, presence.UUID, presence.Event)
+	case "state-change":
+		// joins the UUID if the join event was missed
+		changes = roster.join(changes, presence.Channel, presence.UUID, state)
+	case "interval":
+		for _, uuid := range presence.Join {
+			changes = roster.join(changes, presence.Channel, uuid, nil)
+		}
+		for _, uuid := range presence.Leave {
+			changes = roster.leave(changes, presence.Channel, uuid, "leave")
+		}
+		for _, uuid := range presence.Timeout {
+			changes = roster.leave(changes, presence.Channel, uuid, "timeout")
+		}
+	}
+	roster.occupancy = presence.Occupancy
+	for _, change := range changes {
+		change.Occupancy = roster.occupancy
+	}
+	t.Unlock()
+
+	t.notify(changes)
+
+	if presence.HereNowRefresh {
+		if err := t.refresh([]string{presence.Channel}); err != nil {
+			t.pubnub.Config.Log.Println("presence tracker: refresh failed", err)
+		}
+	}
+}
+
+func (t *PresenceTracker) onStatus(status *PNStatus) {
+	if status.Category != PNReconnectedCategory {
+		return
+	}
+	// the events sent while disconnected were missed
+	if err := t.refresh(t.channels); err != nil {
+		t.pubnub.Config.Log.Println("presence tracker: refresh failed", err)
+	}
+}
+
+// refresh replaces the rosters of channels with the result of HereNow and
+// notifies the differences.
+func (t *PresenceTracker) refresh(channels []string) error {
+	res, _, err := t.pubnub.HereNow().Channels(channels).
+		IncludeUUIDs(true).IncludeState(true).Execute()
+	if err != nil {
+		return err
+	}
+
+	// HereNow omits the empty channels when asked for several
+	occupants := make(map[string]HereNowChannelData)
+	for _, data := range res.Channels {
+		occupants[data.ChannelName] = data
+	}
+
+	var changes []*PNPresenceChange
+	t.Lock()
+	for _, ch := range channels {
+		roster, ok := t.rosters[ch]
+		if !ok {
+			continue
+		}
+		data := occupants[ch]
+		present := make(map[string]bool, len(data.Occupants))
+		for _, occupant := range data.Occupants {
+			present[occupant.UUID] = true
+			changes = roster.join(changes, ch, occupant.UUID, occupant.State)
+		}
+		for uuid := range roster.members {
+			if !present[uuid] {
+				changes = roster.leave(changes, ch, uuid, "leave")
+			}
+		}
+		roster.occupancy = data.Occupancy
+	}
+	for _, change := range changes {
+		change.Occupancy = t.rosters[change.Channel].occupancy
+	}
+	t.Unlock()
+
+	t.notify(changes)
+	return nil
+}
+
+func (t *PresenceTracker) notify(changes []*PNPresenceChange) {
+	if t.OnChange == nil {
+		return
+	}
+	for _, change := range changes {
+		t.OnChange(change)
+	}
+}
+
+// join adds uuid to the roster, or updates its state if it's already present.
+func (r *presenceRoster) join(changes []*PNPresenceChange, channel, uuid string, state map[string]interface{}) []*PNPresenceChange {
+	if _, ok := r.members[uuid]; ok {
+		return r.setState(changes, channel, uuid, state)
+	}
+	r.members[uuid] = copyState(state)
+	return append(changes, &PNPresenceChange{
+		Channel: channel,
+		UUID:    uuid,
+		Event:   "join",
+		State:   copyState(state),
+	})
+}
+
+func (r *presenceRoster) leave(changes []*PNPresenceChange, channel, uuid, event string) []*PNPresenceChange {
+	if _, ok := r.members[uuid]; !ok {
+		return changes
+	}
+	delete(r.members, uuid)
+	return append(changes, &PNPresenceChange{
+		Channel: channel,
+		UUID:    uuid,
+		Event:   event,
+	})
+}
+
+func (r *presenceRoster) setState(changes []*PNPresenceChange, channel, uuid string, state map[string]interface{}) []*PNPresenceChange {
+	if len(state) == 0 || reflect.DeepEqual(r.members[uuid], state) {
+		return changes
+	}
+	r.members[uuid] = copyState(state)
+	return append(changes, &PNPresenceChange{
+		Channel: channel,
+		UUID:    uuid,
+		Event:   "state-change",
+		State:   copyState(state),
+	})
+}
+
+func copyState(state map[string]interface{}) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(state))
+	for k, v := range state {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,151 @@
+package pubnub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPresenceTrackerStub(body string) *stubs.Interceptor {
+	interceptor := stubs.NewInterceptor()
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/presence/sub_key/demo/channel/ch",
+		Query:              "state=1&disable-uuids=0",
+		ResponseBody:       body,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+	return interceptor
+}
+
+func presencePayload(action string, fields map[string]interface{}) subscribeMessage {
+	payload := map[string]interface{}{
+		"action":    action,
+		"timestamp": float64(15078947309567840),
+	}
+	for k, v := range fields {
+		payload[k] = v
+	}
+	return subscribeMessage{
+		Channel:           "ch-pnpres",
+		SubscriptionMatch: "ch-pnpres",
+		Payload:           payload,
+	}
+}
+
+func TestPresenceTrackerAppliesEvents(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := newPresenceTrackerStub(`{"status": 200, "message": "OK", "service": "Presence", "uuids": [{"uuid": "a", "state": {"mood": "happy"}}, {"uuid": "b"}], "occupancy": 2}`)
+	pn := NewPubNub(NewDemoConfig())
+	pn.SetClient(interceptor.GetClient())
+
+	changes := make(chan *PNPresenceChange, 20)
+	tracker := pn.NewPresenceTracker([]string{"ch"})
+	tracker.OnChange = func(change *PNPresenceChange) {
+		changes <- change
+	}
+	assert.Nil(tracker.Start())
+	defer tracker.Stop()
+
+	next := func() *PNPresenceChange {
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(2 * time.Second):
+			assert.Fail("presence change not notified")
+			return &PNPresenceChange{}
+		}
+	}
+
+	assert.Equal([]string{"a", "b"}, tracker.Members("ch"))
+	assert.Equal(2, tracker.Occupancy("ch"))
+	assert.Equal(map[string]interface{}{"mood": "happy"}, tracker.State("ch", "a"))
+	next()
+	next()
+
+	m := pn.subscriptionManager
+	processSubscribePayload(m, presencePayload("join", map[string]interface{}{
+		"uuid": "c", "occupancy": float64(3),
+	}))
+	change := next()
+	assert.Equal(PNPresenceChange{Channel: "ch", UUID: "c", Event: "join", Occupancy: 3}, *change)
+
+	processSubscribePayload(m, presencePayload("state-change", map[string]interface{}{
+		"uuid": "b", "occupancy": float64(3), "data": map[string]interface{}{"mood": "sad"},
+	}))
+	change = next()
+	assert.Equal("state-change", change.Event)
+	assert.Equal("b", change.UUID)
+	assert.Equal(map[string]interface{}{"mood": "sad"}, change.State)
+
+	processSubscribePayload(m, presencePayload("timeout", map[string]interface{}{
+		"uuid": "c", "occupancy": float64(2),
+	}))
+	change = next()
+	assert.Equal(PNPresenceChange{Channel: "ch", UUID: "c", Event: "timeout", Occupancy: 2}, *change)
+
+	processSubscribePayload(m, presencePayload("interval", map[string]interface{}{
+		"occupancy": float64(2),
+		"join":      []interface{}{"d"},
+		"leave":     []interface{}{"a"},
+	}))
+	change = next()
+	assert.Equal(PNPresenceChange{Channel: "ch", UUID: "d", Event: "join", Occupancy: 2}, *change)
+	change = next()
+	assert.Equal(PNPresenceChange{Channel: "ch", UUID: "a", Event: "leave", Occupancy: 2}, *change)
+
+	assert.Equal([]string{"b", "d"}, tracker.Members("ch"))
+	assert.Equal(2, tracker.Occupancy("ch"))
+	assert.Nil(tracker.State("ch", "a"))
+	assert.Nil(tracker.Members("other"))
+}
+
+func TestPresenceTrackerHereNowRefresh(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := newPresenceTrackerStub(`{"status": 200, "message": "OK", "service": "Presence", "uuids": [{"uuid": "a"}, {"uuid": "b"}], "occupancy": 2}`)
+	pn := NewPubNub(NewDemoConfig())
+	pn.SetClient(interceptor.GetClient())
+
+	var changes []PNPresenceChange
+	tracker := pn.NewPresenceTracker([]string{"ch"})
+	tracker.OnChange = func(change *PNPresenceChange) {
+		changes = append(changes, *change)
+	}
+
+	tracker.onPresence(&PNPresence{Event: "join", Channel: "ch", UUID: "x", Occupancy: 1})
+	tracker.onPresence(&PNPresence{Event: "interval", Channel: "ch", Occupancy: 40, HereNowRefresh: true})
+
+	assert.Equal([]string{"a", "b"}, tracker.Members("ch"))
+	assert.Equal(2, tracker.Occupancy("ch"))
+	assert.Equal([]PNPresenceChange{
+		{Channel: "ch", UUID: "x", Event: "join", Occupancy: 1},
+		{Channel: "ch", UUID: "a", Event: "join", State: map[string]interface{}{}, Occupancy: 2},
+		{Channel: "ch", UUID: "b", Event: "join", State: map[string]interface{}{}, Occupancy: 2},
+		{Channel: "ch", UUID: "x", Event: "leave", Occupancy: 2},
+	}, changes)
+}
+
+func TestProcessSubscribePayloadIntervalLists(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	processSubscribePayload(pn.subscriptionManager, presencePayload("interval", map[string]interface{}{
+		"occupancy": float64(2),
+		"join":      []interface{}{"a", "b"},
+		"timeout":   []interface{}{"c"},
+	}))
+
+	select {
+	case presence := <-listener.Presence:
+		assert.Equal([]string{"a", "b"}, presence.Join)
+		assert.Nil(presence.Leave)
+		assert.Equal([]string{"c"}, presence.Timeout)
+	case <-time.After(2 * time.Second):
+		assert.Fail("presence not delivered")
+	}
+}
@@ -124,6 +124,13 @@ func (b *publishFileMessageBuilder) QueryParam(queryParam map[string]string) *pu
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *publishFileMessageBuilder) RetryPolicy(policy RetryPolicy) *publishFileMessageBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the PublishFileMessage request.
 func (b *publishFileMessageBuilder) Execute() (*PublishFileMessageResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -148,6 +155,7 @@ type publishFileMessageOpts struct {
 	FileID         string
 	FileName       string
 	QueryParam     map[string]string
+	RetryPolicy    *RetryPolicy
 	Transport      http.RoundTripper
 	ctx            Context
 }
@@ -226,7 +234,7 @@ func (o *publishFileMessageOpts) buildPath() (string, error) {
 		}
 	}
 
-	if cipherKey := o.pubnub.Config.CipherKey; cipherKey != "" {
+	if module := o.pubnub.Config.cryptoModule(); module != nil {
 		var msg string
 		var p *publishBuilder
 		if o.context() != nil {
@@ -236,7 +244,7 @@ func (o *publishFileMessageOpts) buildPath() (string, error) {
 		}
 		p.opts.Message = o.Message
 
-		msg, errJSONMarshal := p.opts.encryptProcessing(cipherKey)
+		msg, errJSONMarshal := p.opts.encryptProcessing(module)
 		if errJSONMarshal != nil {
 			return "", errJSONMarshal
 		}
@@ -325,6 +333,17 @@ func (o *publishFileMessageOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *publishFileMessageOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *publishFileMessageOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PublishFileMessageResponse is the response to PublishFileMessage request.
 type PublishFileMessageResponse struct {
 	Timestamp int64
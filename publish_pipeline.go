@@ -0,0 +1,200 @@
+package pubnub
+
+import (
+	"sync"
+
+	"github.com/pubnub/go/v7/pnerr"
+)
+
+const defaultPublishPipelineSize = 100
+
+// PublishFuture is the pending result of a Publish executed with
+// ExecuteAsync.
+type PublishFuture struct {
+	done     chan struct{}
+	response *PublishResponse
+	status   StatusResponse
+	err      error
+}
+
+func newPublishFuture() *PublishFuture {
+	return &PublishFuture{
+		done: make(chan struct{}),
+	}
+}
+
+// Done is closed once the publish completed.
+func (f *PublishFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the publish to complete and returns its result.
+func (f *PublishFuture) Result() (*PublishResponse, StatusResponse, error) {
+	<-f.done
+	return f.response, f.status, f.err
+}
+
+func (f *PublishFuture) complete(response *PublishResponse, status StatusResponse, err error) {
+	f.response = response
+	f.status = status
+	f.err = err
+	close(f.done)
+}
+
+// PublishPipeline sends the publishes executed with ExecuteAsync. The
+// publishes to a channel are sent one at a time in the order they were
+// submitted, the ones to different channels concurrently through the request
+// workers. At most Config.PublishPipelineSize publishes are pending per
+// channel, ExecuteAsync blocks once it's reached.
+type PublishPipeline struct {
+	sync.Mutex
+
+	pubnub   *PubNub
+	channels map[string]*channelPipeline
+}
+
+type channelPipeline struct {
+	// slots bounds the pending publishes, a slot is taken before queueing.
+	slots   chan struct{}
+	jobs    []*publishJob
+	last    *PublishFuture
+	running bool
+	// waiting is the number of submissions waiting for a slot.
+	waiting int
+}
+
+type publishJob struct {
+	builder *publishBuilder
+	future  *PublishFuture
+}
+
+func newPublishPipeline(pubnub *PubNub) *PublishPipeline {
+	return &PublishPipeline{
+		pubnub:   pubnub,
+		channels: make(map[string]*channelPipeline),
+	}
+}
+
+func (p *PublishPipeline) size() int {
+	p.pubnub.Config.RLock()
+	defer p.pubnub.Config.RUnlock()
+
+	if p.pubnub.Config.PublishPipelineSize <= 0 {
+		return defaultPublishPipelineSize
+	}
+	return p.pubnub.Config.PublishPipelineSize
+}
+
+// submit queues the publish of builder after the pending ones to the same
+// channel. It waits for a slot unless the context of the builder is done.
+func (p *PublishPipeline) submit(builder *publishBuilder) *PublishFuture {
+	future := newPublishFuture()
+	channel := builder.opts.Channel
+	size := p.size()
+
+	p.Lock()
+	cp, ok := p.channels[channel]
+	if !ok {
+		cp = &channelPipeline{slots: make(chan struct{}, size)}
+		p.channels[channel] = cp
+	}
+	cp.waiting++
+	p.Unlock()
+
+	var done <-chan struct{}
+	if builder.opts.ctx != nil {
+		done = builder.opts.ctx.Done()
+	}
+	var err error
+	select {
+	case cp.slots <- struct{}{}:
+	case <-done:
+		err = builder.opts.ctx.Err()
+	case <-p.pubnub.ctx.Done():
+		err = p.pubnub.ctx.Err()
+	}
+
+	p.Lock()
+	cp.waiting--
+	if err != nil {
+		p.release(channel, cp)
+		p.Unlock()
+		e := pnerr.NewConnectionError("Waiting for the publish pipeline", err)
+		future.complete(emptyPublishResponse,
+			createStatus(PNCancelledCategory, "", ResponseInfo{Operation: PNPublishOperation}, e), e)
+		return future
+	}
+	cp.jobs = append(cp.jobs, &publishJob{builder: builder, future: future})
+	cp.last = future
+	if !cp.running {
+		cp.running = true
+		go p.run(channel, cp)
+	}
+	p.Unlock()
+
+	return future
+}
+
+// run sends the queued publishes of channel one after the other.
+func (p *PublishPipeline) run(channel string, cp *channelPipeline) {
+	for {
+		p.Lock()
+		if len(cp.jobs) == 0 {
+			cp.running = false
+			p.release(channel, cp)
+			p.Unlock()
+			return
+		}
+		job := cp.jobs[0]
+		cp.jobs = cp.jobs[1:]
+		p.Unlock()
+
+		select {
+		case <-p.pubnub.ctx.Done():
+			e := pnerr.NewConnectionError("Publish pipeline closed", p.pubnub.ctx.Err())
+			job.future.complete(emptyPublishResponse,
+				createStatus(PNCancelledCategory, "", ResponseInfo{Operation: PNPublishOperation}, e), e)
+		default:
+			job.future.complete(job.builder.Execute())
+		}
+		<-cp.slots
+	}
+}
+
+// release drops the pipeline of channel once it's idle, p has to be locked.
+func (p *PublishPipeline) release(channel string, cp *channelPipeline) {
+	if cp.running || cp.waiting > 0 || len(cp.jobs) > 0 {
+		return
+	}
+	if p.channels[channel] == cp {
+		delete(p.channels, channel)
+	}
+}
+
+// Flush waits for the publishes submitted so far to complete, or for ctx to
+// be done.
+func (p *PublishPipeline) Flush(ctx Context) error {
+	p.Lock()
+	futures := make([]*PublishFuture, 0, len(p.channels))
+	for _, cp := range p.channels {
+		if cp.last != nil {
+			futures = append(futures, cp.last)
+		}
+	}
+	p.Unlock()
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	// the publishes to a channel complete in order, waiting for the last
+	// one is enough
+	for _, future := range futures {
+		select {
+		case <-future.Done():
+		case <-done:
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package pubnub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var publishURLPattern = regexp.MustCompile(`/publish/demo/demo/0/([^/]+)/0/([^?]+)`)
+
+// publishRecorder is a transport recording the publishes by channel, the
+// publishes to "slow" take longer to reorder them if sent concurrently.
+type publishRecorder struct {
+	sync.Mutex
+
+	messages   map[string][]string
+	inFlight   map[string]int
+	overlapped bool
+	release    chan struct{}
+}
+
+func newPublishRecorder() *publishRecorder {
+	return &publishRecorder{
+		messages: make(map[string][]string),
+		inFlight: make(map[string]int),
+	}
+}
+
+func (r *publishRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	match := publishURLPattern.FindStringSubmatch(req.URL.String())
+	if match == nil {
+		return nil, errors.New("not a publish")
+	}
+	channel, message := match[1], match[2]
+
+	r.Lock()
+	r.inFlight[channel]++
+	if r.inFlight[channel] > 1 {
+		r.overlapped = true
+	}
+	r.Unlock()
+
+	if r.release != nil {
+		<-r.release
+	}
+	if channel == "slow" {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	r.Lock()
+	r.inFlight[channel]--
+	r.messages[channel] = append(r.messages[channel], message)
+	r.Unlock()
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`[1,"Sent","15000000000000000"]`)),
+		Request:    req,
+	}, nil
+}
+
+func TestPublishExecuteAsyncOrder(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+	recorder := newPublishRecorder()
+	pn.SetClient(&http.Client{Transport: recorder})
+
+	var futures []*PublishFuture
+	var expected []string
+	for i := 0; i < 20; i++ {
+		message := fmt.Sprintf("%%22m%d%%22", i)
+		expected = append(expected, message)
+		futures = append(futures, pn.Publish().Channel("slow").Message(fmt.Sprintf("m%d", i)).ExecuteAsync())
+		futures = append(futures, pn.Publish().Channel("fast").Message(fmt.Sprintf("m%d", i)).ExecuteAsync())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(pn.PublishPipeline().Flush(ctx))
+
+	for _, future := range futures {
+		select {
+		case <-future.Done():
+		default:
+			assert.Fail("publish not completed by Flush")
+		}
+		res, _, err := future.Result()
+		assert.Nil(err)
+		if assert.NotNil(res) {
+			assert.Equal(int64(15000000000000000), res.Timestamp)
+		}
+	}
+
+	recorder.Lock()
+	defer recorder.Unlock()
+	assert.Equal(expected, recorder.messages["slow"])
+	assert.Equal(expected, recorder.messages["fast"])
+	assert.False(recorder.overlapped)
+
+	pn.PublishPipeline().Lock()
+	assert.Equal(0, len(pn.PublishPipeline().channels))
+	pn.PublishPipeline().Unlock()
+}
+
+func TestPublishExecuteAsyncBuilderReuse(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	defer pn.Destroy()
+	recorder := newPublishRecorder()
+	pn.SetClient(&http.Client{Transport: recorder})
+
+	builder := pn.Publish().Channel("ch").Message("first")
+	first := builder.ExecuteAsync()
+	second := builder.Message("second").ExecuteAsync()
+
+	_, _, err := first.Result()
+	assert.Nil(err)
+	_, _, err = second.Result()
+	assert.Nil(err)
+
+	recorder.Lock()
+	defer recorder.Unlock()
+	assert.Equal([]string{"%22first%22", "%22second%22"}, recorder.messages["ch"])
+}
+
+func TestPublishPipelineBounded(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.PublishPipelineSize = 2
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	recorder := newPublishRecorder()
+	recorder.release = make(chan struct{})
+	pn.SetClient(&http.Client{Transport: recorder})
+
+	first := pn.Publish().Channel("ch").Message("1").ExecuteAsync()
+	pn.Publish().Channel("ch").Message("2").ExecuteAsync()
+
+	// the pipeline of ch is full, the submission gives up with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, status, err := pn.PublishWithContext(ctx).Channel("ch").Message("3").ExecuteAsync().Result()
+	assert.Equal(PNCancelledCategory, status.Category)
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+
+	// Flush gives up with its context too
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer flushCancel()
+	assert.Equal(context.DeadlineExceeded, pn.PublishPipeline().Flush(flushCtx))
+
+	close(recorder.release)
+	assert.Nil(pn.PublishPipeline().Flush(context.Background()))
+	_, _, err = first.Result()
+	assert.Nil(err)
+
+	recorder.Lock()
+	defer recorder.Unlock()
+	assert.Equal([]string{"%221%22", "%222%22"}, recorder.messages["ch"])
+}
@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/pubnub/go/v7/crypto"
 	"github.com/pubnub/go/v7/pnerr"
 	"github.com/pubnub/go/v7/utils"
 
@@ -20,6 +21,16 @@ import (
 const publishGetPath = "/publish/%s/%s/0/%s/%s/%s"
 const publishPostPath = "/publish/%s/%s/0/%s/%s"
 
+const (
+	// maxPublishSize is the largest message accepted by the service, in bytes
+	// as sent with the URL encoded channel name.
+	maxPublishSize = 32 * 1024
+	// maxPublishGETSize is the size of the URL encoded message and channel
+	// above which the publishes are sent with POST, to keep the URL in the
+	// limits of the proxies.
+	maxPublishGETSize = 2 * 1024
+)
+
 var emptyPublishResponse *PublishResponse
 
 type publishOpts struct {
@@ -38,8 +49,14 @@ type publishOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 
+	// set by validate, the message as sent and whether its size requires POST
+	payload  *string
+	autoPost bool
+
 	// nil hacks
 	setTTL         bool
 	setShouldStore bool
@@ -176,8 +193,19 @@ func (b *publishBuilder) QueryParam(queryParam map[string]string) *publishBuilde
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *publishBuilder) RetryPolicy(policy RetryPolicy) *publishBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Publish request.
 func (b *publishBuilder) Execute() (*PublishResponse, StatusResponse, error) {
+	if status, err := b.opts.pubnub.publishRateLimiter.acquire(b.opts.ctx, b.opts.Channel, PNPublishOperation); err != nil {
+		return emptyPublishResponse, status, err
+	}
+
 	rawJSON, status, err := executeRequest(b.opts)
 	if err != nil {
 		return emptyPublishResponse, status, err
@@ -186,6 +214,16 @@ func (b *publishBuilder) Execute() (*PublishResponse, StatusResponse, error) {
 	return newPublishResponse(rawJSON, status)
 }
 
+// ExecuteAsync queues the Publish request in the PublishPipeline and returns
+// without waiting for it. The publishes to a channel are sent in the order
+// ExecuteAsync was called.
+func (b *publishBuilder) ExecuteAsync() *PublishFuture {
+	// the builder may be reused before the request is sent
+	opts := *b.opts
+
+	return b.opts.pubnub.publishPipeline.submit(&publishBuilder{opts: &opts})
+}
+
 func (o *publishOpts) config() Config {
 	return *o.pubnub.Config
 }
@@ -215,16 +253,84 @@ func (o *publishOpts) validate() error {
 		return newValidationError(o, StrMissingMessage)
 	}
 
+	o.payload = nil
+	msg, err := o.message()
+	if err != nil {
+		return err
+	}
+	o.payload = &msg
+
+	channel := utils.URLEncode(o.Channel)
+	escaped := utils.URLEncode(msg)
+	o.autoPost = !o.UsePost && len(escaped)+len(channel) > maxPublishGETSize
+
+	// the message is sent URL encoded in the path of a GET, as is in the body
+	// of a POST
+	size := len(channel) + len(escaped)
+	if o.usePost() {
+		size = len(channel) + len(msg)
+	}
+	if size > maxPublishSize {
+		return pnerr.NewPayloadTooLargeError(o.operationType().String(), size, maxPublishSize)
+	}
+	if o.autoPost {
+		o.pubnub.Config.Log.Println("publish: message too large for GET, sending with POST")
+	}
+
 	return nil
 }
 
-func (o *publishOpts) encryptProcessing(cipherKey string) (string, error) {
+// usePost reports whether the request is sent with POST, as asked or because
+// the message is too large for the URL.
+func (o *publishOpts) usePost() bool {
+	return o.UsePost || o.autoPost
+}
+
+// message returns the message as sent: serialized, and encrypted when a
+// crypto module is set. It's computed once by validate.
+func (o *publishOpts) message() (string, error) {
+	if o.payload != nil {
+		return *o.payload, nil
+	}
+
+	if module := o.pubnub.Config.cryptoModule(); module != nil {
+		msg, err := o.encryptProcessing(module)
+		if err != nil {
+			return "", err
+		}
+		o.pubnub.Config.Log.Println("EncryptString: encrypted", msg)
+		return msg, nil
+	}
+
+	return o.serialized(o.Message)
+}
+
+// serialized returns msg serialized with Config.Serializer, or as is when
+// Serialize is false and it's already serialized.
+func (o *publishOpts) serialized(msg interface{}) (string, error) {
+	if o.Serialize {
+		jsonEncBytes, errEnc := o.pubnub.Config.serializer().Marshal(msg)
+		if errEnc != nil {
+			o.pubnub.Config.Log.Printf("ERROR: Publish error: %s\n", errEnc.Error())
+			return "", errEnc
+		}
+		o.pubnub.Config.Log.Println("len(jsonEncBytes)", len(jsonEncBytes))
+		return string(jsonEncBytes), nil
+	}
+
+	if serializedMsg, ok := msg.(string); ok {
+		return serializedMsg, nil
+	}
+	return "", pnerr.NewBuildRequestError("Message is not JSON serialized.")
+}
+
+func (o *publishOpts) encryptProcessing(module crypto.CryptoModule) (string, error) {
 	var msg string
 	var errJSONMarshal error
 
 	o.pubnub.Config.Log.Println("EncryptString: encrypting", fmt.Sprintf("%s", o.Message))
 	if o.pubnub.Config.DisablePNOtherProcessing {
-		if msg, errJSONMarshal = utils.SerializeEncryptAndSerialize(o.Message, cipherKey, o.Serialize, o.pubnub.Config.UseRandomInitializationVector); errJSONMarshal != nil {
+		if msg, errJSONMarshal = o.encryptMessage(module); errJSONMarshal != nil {
 			o.pubnub.Config.Log.Printf("error in serializing: %v\n", errJSONMarshal)
 			return "", errJSONMarshal
 		}
@@ -238,27 +344,37 @@ func (o *publishOpts) encryptProcessing(cipherKey string) (string, error) {
 
 			if ok {
 				o.pubnub.Config.Log.Println(ok, msgPart)
-				encMsg, errJSONMarshal := utils.SerializeAndEncrypt(msgPart, cipherKey, o.Serialize, o.pubnub.Config.UseRandomInitializationVector)
+				serializedPart, errJSONMarshal := o.serialized(msgPart)
+				if errJSONMarshal != nil {
+					o.pubnub.Config.Log.Printf("error in serializing: %v\n", errJSONMarshal)
+					return "", errJSONMarshal
+				}
+				encMsg, errJSONMarshal := encryptString(module, serializedPart)
 				if errJSONMarshal != nil {
 					o.pubnub.Config.Log.Printf("error in serializing: %v\n", errJSONMarshal)
 					return "", errJSONMarshal
 				}
-				v["pn_other"] = encMsg
-				jsonEncBytes, errEnc := json.Marshal(v)
+				// the message is left as is, it's encrypted again on retries
+				encrypted := make(map[string]interface{}, len(v))
+				for key, value := range v {
+					encrypted[key] = value
+				}
+				encrypted["pn_other"] = encMsg
+				jsonEncBytes, errEnc := o.pubnub.Config.serializer().Marshal(encrypted)
 				if errEnc != nil {
 					o.pubnub.Config.Log.Printf("ERROR: Publish error: %s\n", errEnc.Error())
 					return "", errEnc
 				}
 				msg = string(jsonEncBytes)
 			} else {
-				if msg, errJSONMarshal = utils.SerializeEncryptAndSerialize(o.Message, cipherKey, o.Serialize, o.pubnub.Config.UseRandomInitializationVector); errJSONMarshal != nil {
+				if msg, errJSONMarshal = o.encryptMessage(module); errJSONMarshal != nil {
 					o.pubnub.Config.Log.Printf("error in serializing: %v\n", errJSONMarshal)
 					return "", errJSONMarshal
 				}
 			}
 			break
 		default:
-			if msg, errJSONMarshal = utils.SerializeEncryptAndSerialize(o.Message, cipherKey, o.Serialize, o.pubnub.Config.UseRandomInitializationVector); errJSONMarshal != nil {
+			if msg, errJSONMarshal = o.encryptMessage(module); errJSONMarshal != nil {
 				o.pubnub.Config.Log.Printf("error in serializing: %v\n", errJSONMarshal)
 				return "", errJSONMarshal
 			}
@@ -269,8 +385,26 @@ func (o *publishOpts) encryptProcessing(cipherKey string) (string, error) {
 	return msg, nil
 }
 
+// encryptMessage returns the message serialized, encrypted and serialized
+// again as a JSON string.
+func (o *publishOpts) encryptMessage(module crypto.CryptoModule) (string, error) {
+	serialized, err := o.serialized(o.Message)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := encryptString(module, serialized)
+	if err != nil {
+		return "", err
+	}
+	jsonEncBytes, err := json.Marshal(encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonEncBytes), nil
+}
+
 func (o *publishOpts) buildPath() (string, error) {
-	if o.UsePost == true {
+	if o.usePost() {
 		return fmt.Sprintf(publishPostPath,
 			o.pubnub.Config.PublishKey,
 			o.pubnub.Config.SubscribeKey,
@@ -278,32 +412,12 @@ func (o *publishOpts) buildPath() (string, error) {
 			"0"), nil
 	}
 
-	var msg string
-	var errJSONMarshal error
-
-	if cipherKey := o.pubnub.Config.CipherKey; cipherKey != "" {
-		if msg, errJSONMarshal = o.encryptProcessing(cipherKey); errJSONMarshal != nil {
-			return "", errJSONMarshal
-		}
-
-		o.pubnub.Config.Log.Println("EncryptString: encrypted", msg)
-	} else {
-		if o.Serialize {
-			jsonEncBytes, errEnc := json.Marshal(o.Message)
-			if errEnc != nil {
-				o.pubnub.Config.Log.Printf("ERROR: Publish error: %s\n", errEnc.Error())
-				return "", errEnc
-			}
-			msg = string(jsonEncBytes)
-			o.pubnub.Config.Log.Println("len(jsonEncBytes)", len(jsonEncBytes))
-
-		} else {
-			if serializedMsg, ok := o.Message.(string); ok {
-				msg = serializedMsg
-			} else {
-				return "", pnerr.NewBuildRequestError("buildpath: Message is not JSON serialized.")
-			}
+	msg, err := o.message()
+	if err != nil {
+		if _, ok := err.(*pnerr.BuildRequestError); ok {
+			return "", pnerr.NewBuildRequestError("buildpath: Message is not JSON serialized.")
 		}
+		return "", err
 	}
 
 	return fmt.Sprintf(publishGetPath,
@@ -318,7 +432,7 @@ func (o *publishOpts) buildQuery() (*url.Values, error) {
 	q := defaultQuery(o.pubnub.Config.UUID, o.pubnub.telemetryManager)
 
 	if o.Meta != nil {
-		meta, err := utils.ValueAsString(o.Meta)
+		meta, err := valueAsString(o.pubnub.Config.serializer(), o.Meta)
 		if err != nil {
 			return &url.Values{}, err
 		}
@@ -359,27 +473,15 @@ func (o *publishOpts) jobQueue() chan *JobQItem {
 }
 
 func (o *publishOpts) buildBody() ([]byte, error) {
-	if o.UsePost {
-		if cipherKey := o.pubnub.Config.CipherKey; cipherKey != "" {
-			msg, errJSONMarshal := o.encryptProcessing(cipherKey)
-			if errJSONMarshal != nil {
-				return []byte{}, errJSONMarshal
-			}
-			return []byte(msg), nil
-		}
-		if o.Serialize {
-			jsonEncBytes, errEnc := json.Marshal(o.Message)
-			if errEnc != nil {
-				o.pubnub.Config.Log.Printf("ERROR: Publish error: %s\n", errEnc.Error())
-				return []byte{}, errEnc
+	if o.usePost() {
+		msg, err := o.message()
+		if err != nil {
+			if _, ok := err.(*pnerr.BuildRequestError); ok {
+				return []byte{}, pnerr.NewBuildRequestError("buildBody: Message is not JSON serialized.")
 			}
-			return jsonEncBytes, nil
+			return []byte{}, err
 		}
-		serializedMsg, ok := o.Message.(string)
-		if ok {
-			return []byte(serializedMsg), nil
-		}
-		return []byte{}, pnerr.NewBuildRequestError("buildBody: Message is not JSON serialized.")
+		return []byte(msg), nil
 	}
 	return []byte{}, nil
 }
@@ -389,7 +491,7 @@ func (o *publishOpts) buildBodyMultipartFileUpload() (bytes.Buffer, *multipart.W
 }
 
 func (o *publishOpts) httpMethod() string {
-	if o.UsePost {
+	if o.usePost() {
 		return "POST"
 	}
 	return "GET"
@@ -418,3 +520,14 @@ func (o *publishOpts) telemetryManager() *TelemetryManager {
 func (o *publishOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *publishOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *publishOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
@@ -1,10 +1,14 @@
 package pubnub
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/pubnub/go/v7/pnerr"
 	h "github.com/pubnub/go/v7/tests/helpers"
 	"github.com/stretchr/testify/assert"
 )
@@ -543,3 +547,95 @@ func TestPublishValidateSubscribeKey(t *testing.T) {
 
 	assert.Equal("pubnub/validation: pubnub: Publish: Missing Subscribe Key", opts.validate().Error())
 }
+
+func TestPublishAutoPost(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	opts := &publishOpts{
+		Channel:   "ch",
+		Message:   "hey",
+		Serialize: true,
+		pubnub:    pn,
+	}
+	assert.Nil(opts.validate())
+	assert.Equal("GET", opts.httpMethod())
+
+	// the URL encoding of the quotes triples their size
+	message := strings.Repeat(`"`, 700)
+	opts.Message = message
+	assert.Nil(opts.validate())
+	assert.Equal("POST", opts.httpMethod())
+
+	path, err := opts.buildPath()
+	assert.Nil(err)
+	assert.Equal("/publish/demo/demo/0/ch/0", path)
+	body, err := opts.buildBody()
+	assert.Nil(err)
+	expected, _ := json.Marshal(message)
+	assert.Equal(expected, body)
+
+	// the choice is made again for each request
+	opts.Message = "hey"
+	assert.Nil(opts.validate())
+	assert.Equal("GET", opts.httpMethod())
+}
+
+func TestPublishValidateSize(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	opts := &publishOpts{
+		Channel:   "ch",
+		Message:   strings.Repeat("a", maxPublishSize-4),
+		Serialize: true,
+		pubnub:    pn,
+	}
+	assert.Nil(opts.validate())
+
+	opts.Message = strings.Repeat("a", maxPublishSize-3)
+	err := opts.validate()
+	var sizeErr *pnerr.PayloadTooLargeError
+	if assert.True(errors.As(err, &sizeErr)) {
+		assert.Equal(maxPublishSize+1, sizeErr.Size)
+		assert.Equal(maxPublishSize, sizeErr.MaxSize)
+	}
+	assert.Equal(fmt.Sprintf("pubnub/validation: pubnub: Publish: Message too large: %d bytes, the maximum is %d",
+		maxPublishSize+1, maxPublishSize), err.Error())
+	assert.False(pnerr.IsRetryable(err))
+
+	// the size is the encoded one: the multibyte message is sent as is with
+	// POST, the channel is URL encoded
+	opts.Message = strings.Repeat("é", (maxPublishSize-6)/2)
+	assert.Nil(opts.validate())
+	assert.True(opts.usePost())
+	opts.Channel = "ché"
+	assert.True(len(opts.Channel)+len(*opts.payload) <= maxPublishSize)
+	if assert.True(errors.As(opts.validate(), &sizeErr)) {
+		assert.Equal(maxPublishSize+4, sizeErr.Size)
+	}
+	opts.Channel = "ch"
+
+	// the encryption and base64 overhead counts
+	pn.Config.CipherKey = "testCipher"
+	opts.Message = strings.Repeat("a", 3*maxPublishSize/4)
+	assert.True(errors.As(opts.validate(), &sizeErr))
+}
+
+func TestPublishEncryptPNOtherUnchanged(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.CipherKey = "testCipher"
+	pn := NewPubNub(config)
+
+	message := map[string]interface{}{"pn_other": "secret", "id": 1}
+	opts := &publishOpts{
+		Channel:   "ch",
+		Message:   message,
+		Serialize: true,
+		pubnub:    pn,
+	}
+	assert.Nil(opts.validate())
+	assert.NotContains(*opts.payload, "secret")
+	assert.Equal("secret", message["pn_other"])
+}
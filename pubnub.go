@@ -52,6 +52,12 @@ const (
 	StrMissingFileID = "Missing File ID"
 	// StrMissingFileName shows `Missing File Name` message
 	StrMissingFileName = "Missing File Name"
+	// StrMissingFile shows `Missing File` message
+	StrMissingFile = "Missing File"
+	// StrMissingFileSize shows `Missing File Size` message
+	StrMissingFileSize = "Missing File Size"
+	// StrUnknownEncryptedFileSize shows `Unknown Encrypted File Size` message
+	StrUnknownEncryptedFileSize = "Unknown Encrypted File Size"
 	// StrMissingToken shows `Missing PAMv3 token` message
 	StrMissingToken = "Missing PAMv3 token"
 )
@@ -74,6 +80,9 @@ type PubNub struct {
 	ctx                  Context
 	cancel               func()
 	tokenManager         *TokenManager
+	originManager        *OriginManager
+	publishRateLimiter   *publishRateLimiter
+	publishPipeline      *PublishPipeline
 }
 
 // Publish is used to send a message to all subscribers of a channel.
@@ -86,6 +95,11 @@ func (pn *PubNub) PublishWithContext(ctx Context) *publishBuilder {
 	return newPublishBuilderWithContext(pn, ctx)
 }
 
+// PublishPipeline returns the pipeline sending the publishes executed with ExecuteAsync.
+func (pn *PubNub) PublishPipeline() *PublishPipeline {
+	return pn.publishPipeline
+}
+
 // Fire endpoint allows the client to send a message to PubNub Functions Event Handlers. These messages will go directly to any Event Handlers registered on the channel that you fire to and will trigger their execution.
 func (pn *PubNub) Fire() *fireBuilder {
 	return newFireBuilder(pn)
@@ -381,11 +395,26 @@ func (pn *PubNub) ResetTokenManager() {
 	pn.tokenManager.CleanUp()
 }
 
+// NewSubscriptionSet groups subscriptions, the listeners added to the set receive the events of all of them.
+func (pn *PubNub) NewSubscriptionSet(subscriptions ...*Subscription) *SubscriptionSet {
+	set := &SubscriptionSet{pubnub: pn}
+	for _, s := range subscriptions {
+		set.Add(s)
+	}
+	return set
+}
+
 // Unsubscribe When subscribed to a single channel, this function causes the client to issue a leave from the channel and close any open socket to the PubNub Network. For multiplexed channels, the specified channel(s) will be removed and the socket remains open until there are no more channels remaining in the list.
 func (pn *PubNub) Unsubscribe() *unsubscribeBuilder {
 	return newUnsubscribeBuilder(pn)
 }
 
+// NewPresenceTracker creates a PresenceTracker for channels, see
+// PresenceTracker.Start.
+func (pn *PubNub) NewPresenceTracker(channels []string) *PresenceTracker {
+	return newPresenceTracker(pn, channels)
+}
+
 // AddListener lets you add a new listener.
 func (pn *PubNub) AddListener(listener *Listener) {
 	pn.subscriptionManager.AddListener(listener)
@@ -396,11 +425,36 @@ func (pn *PubNub) RemoveListener(listener *Listener) {
 	pn.subscriptionManager.RemoveListener(listener)
 }
 
+// AddEventListener lets you add a new callback based listener. Events without a callback set on the listener are dropped.
+func (pn *PubNub) AddEventListener(listener *EventListener) {
+	pn.subscriptionManager.AddEventListener(listener)
+}
+
+// RemoveEventListener lets you remove a callback based listener.
+func (pn *PubNub) RemoveEventListener(listener *EventListener) {
+	pn.subscriptionManager.RemoveEventListener(listener)
+}
+
 // GetListeners gets all the existing isteners.
 func (pn *PubNub) GetListeners() map[*Listener]bool {
 	return pn.subscriptionManager.GetListeners()
 }
 
+// GetSubscribeState returns the current state of the subscribe loop.
+func (pn *PubNub) GetSubscribeState() SubscribeState {
+	return pn.subscriptionManager.GetState()
+}
+
+// GetSubscribeStateTransition returns the latest transition of the subscribe loop state along with its reason and error.
+func (pn *PubNub) GetSubscribeStateTransition() SubscribeStateTransition {
+	return pn.subscriptionManager.GetLastStateTransition()
+}
+
+// AddSubscribeStateHandler adds a handler which is called on every transition of the subscribe loop state. Handlers must not block.
+func (pn *PubNub) AddSubscribeStateHandler(handler func(SubscribeStateTransition)) {
+	pn.subscriptionManager.AddStateHandler(handler)
+}
+
 // Leave unsubscribes from a channel.
 func (pn *PubNub) Leave() *leaveBuilder {
 	return newLeaveBuilder(pn)
@@ -444,14 +498,11 @@ func (pn *PubNub) GetClient() *http.Client {
 	defer pn.Unlock()
 
 	if pn.client == nil {
+		timeout := pn.Config.NonSubscribeRequestTimeout
 		if pn.Config.UseHTTP2 {
-			pn.client = NewHTTP2Client(pn.Config.ConnectTimeout,
-				pn.Config.SubscribeRequestTimeout)
-		} else {
-			pn.client = NewHTTP1Client(pn.Config.ConnectTimeout,
-				pn.Config.NonSubscribeRequestTimeout,
-				pn.Config.MaxIdleConnsPerHost)
+			timeout = pn.Config.SubscribeRequestTimeout
 		}
+		pn.client = NewHTTPClient(pn.Config, timeout)
 	}
 
 	return pn.client
@@ -470,19 +521,18 @@ func (pn *PubNub) GetSubscribeClient() *http.Client {
 	defer pn.Unlock()
 	if pn.subscribeClient == nil {
 
-		if pn.Config.UseHTTP2 {
-			pn.subscribeClient = NewHTTP2Client(pn.Config.ConnectTimeout,
-				pn.Config.SubscribeRequestTimeout)
-		} else {
-			pn.subscribeClient = NewHTTP1Client(pn.Config.ConnectTimeout,
-				pn.Config.SubscribeRequestTimeout, pn.Config.MaxIdleConnsPerHost)
-		}
+		pn.subscribeClient = NewHTTPClient(pn.Config, pn.Config.SubscribeRequestTimeout)
 
 	}
 
 	return pn.subscribeClient
 }
 
+// GetSuppressedDuplicates returns the number of duplicate messages suppressed by subscribe, see Config.DedupeCacheSize.
+func (pn *PubNub) GetSuppressedDuplicates() int64 {
+	return pn.subscriptionManager.SuppressedDuplicates()
+}
+
 // GetSubscribedChannels gets a list of all subscribed channels.
 func (pn *PubNub) GetSubscribedChannels() []string {
 	return pn.subscriptionManager.getSubscribedChannels()
@@ -767,6 +817,9 @@ func NewPubNub(pnconf *Config) *PubNub {
 	pn.jobQueue = make(chan *JobQItem)
 	pn.requestWorkers = pn.newNonSubQueueProcessor(pnconf.MaxWorkers, ctx)
 	pn.tokenManager = newTokenManager(pn, ctx)
+	pn.originManager = newOriginManager(pn)
+	pn.publishRateLimiter = newPublishRateLimiter(pn)
+	pn.publishPipeline = newPublishPipeline(pn)
 
 	return pn
 }
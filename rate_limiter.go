@@ -0,0 +1,188 @@
+package pubnub
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+)
+
+// maxRateLimitedChannels bounds the number of channel buckets kept, the
+// idle ones are dropped once it's reached.
+const maxRateLimitedChannels = 10000
+
+// RateLimit configures a token bucket of the publish rate limiter.
+type RateLimit struct {
+	Rate  float64 // Requests per second, 0 disables the limit.
+	Burst int     // Requests sent back to back before Rate applies, 0 means 1.
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst < 1 {
+		return 1
+	}
+	return float64(l.Burst)
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		limit:  limit,
+		tokens: limit.burst(),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.limit.burst(), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+// wait returns the time until a token is available. The tokens of the
+// requests already waiting are taken, so it grows with them.
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+// publishRateLimiter throttles the Publish, Signal and Fire requests with a
+// token bucket for the instance and one for each channel.
+type publishRateLimiter struct {
+	sync.Mutex
+
+	pubnub   *PubNub
+	instance *tokenBucket
+	channels map[string]*tokenBucket
+}
+
+func newPublishRateLimiter(pubnub *PubNub) *publishRateLimiter {
+	return &publishRateLimiter{
+		pubnub:   pubnub,
+		channels: make(map[string]*tokenBucket),
+	}
+}
+
+func (l *publishRateLimiter) limits(channel string) (RateLimit, RateLimit, RateLimitMode) {
+	l.pubnub.Config.RLock()
+	defer l.pubnub.Config.RUnlock()
+
+	channelLimit, ok := l.pubnub.Config.ChannelPublishRateLimits[channel]
+	if !ok {
+		channelLimit = l.pubnub.Config.ChannelPublishRateLimit
+	}
+	return l.pubnub.Config.PublishRateLimit, channelLimit, l.pubnub.Config.PublishRateLimitMode
+}
+
+// acquire takes a token for a request of operation to channel, waiting for
+// it according to the RateLimitMode. ctx is the context of the request.
+func (l *publishRateLimiter) acquire(ctx Context, channel string, operation OperationType) (StatusResponse, error) {
+	instanceLimit, channelLimit, mode := l.limits(channel)
+	if instanceLimit.Rate <= 0 && channelLimit.Rate <= 0 {
+		return StatusResponse{}, nil
+	}
+
+	now := time.Now()
+	l.Lock()
+	buckets := l.buckets(channel, instanceLimit, channelLimit, now)
+	var wait time.Duration
+	for _, b := range buckets {
+		if w := b.wait(); w > wait {
+			wait = w
+		}
+	}
+	if wait > 0 && mode == PNRateLimitFailFast {
+		l.Unlock()
+		e := pnerr.NewRateLimitError(channel, wait)
+		return createStatus(PNTooManyRequestsCategory, "", ResponseInfo{Operation: operation}, e), e
+	}
+	// the tokens are taken before waiting for them to queue the requests
+	for _, b := range buckets {
+		b.tokens--
+	}
+	l.Unlock()
+
+	if wait == 0 {
+		return StatusResponse{}, nil
+	}
+
+	var done <-chan struct{}
+	if mode != PNRateLimitBlock && ctx != nil {
+		done = ctx.Done()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		l.pubnub.telemetryManager.StoreRateLimitWait(wait.Seconds(), operation)
+		return StatusResponse{}, nil
+	case <-done:
+		l.release(buckets)
+		e := pnerr.NewConnectionError("Waiting for the publish rate limit", ctx.Err())
+		return createStatus(PNCancelledCategory, "", ResponseInfo{Operation: operation}, e), e
+	case <-l.pubnub.ctx.Done():
+		l.release(buckets)
+		e := pnerr.NewConnectionError("Waiting for the publish rate limit", l.pubnub.ctx.Err())
+		return createStatus(PNCancelledCategory, "", ResponseInfo{Operation: operation}, e), e
+	}
+}
+
+// buckets returns the buckets of the limits which apply, refilled. The
+// buckets are replaced when their limit changed.
+func (l *publishRateLimiter) buckets(channel string, instanceLimit, channelLimit RateLimit, now time.Time) []*tokenBucket {
+	var buckets []*tokenBucket
+
+	if instanceLimit.Rate > 0 {
+		if l.instance == nil || l.instance.limit != instanceLimit {
+			l.instance = newTokenBucket(instanceLimit, now)
+		}
+		l.instance.refill(now)
+		buckets = append(buckets, l.instance)
+	}
+
+	if channelLimit.Rate > 0 {
+		b, ok := l.channels[channel]
+		if !ok || b.limit != channelLimit {
+			if !ok && len(l.channels) >= maxRateLimitedChannels {
+				l.dropIdleChannels(now)
+			}
+			b = newTokenBucket(channelLimit, now)
+			l.channels[channel] = b
+		}
+		b.refill(now)
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// dropIdleChannels drops the buckets of the channels which are full again,
+// they would be recreated the same.
+func (l *publishRateLimiter) dropIdleChannels(now time.Time) {
+	for channel, b := range l.channels {
+		b.refill(now)
+		if b.tokens >= b.limit.burst() {
+			delete(l.channels, channel)
+		}
+	}
+}
+
+// release gives back the tokens of a request which stopped waiting.
+func (l *publishRateLimiter) release(buckets []*tokenBucket) {
+	l.Lock()
+	defer l.Unlock()
+
+	for _, b := range buckets {
+		b.tokens = math.Min(b.limit.burst(), b.tokens+1)
+	}
+}
@@ -0,0 +1,140 @@
+package pubnub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+	b := newTokenBucket(RateLimit{Rate: 2, Burst: 3}, now)
+
+	assert.Equal(time.Duration(0), b.wait())
+	b.tokens -= 3
+	assert.Equal(500*time.Millisecond, b.wait())
+
+	b.refill(now.Add(250 * time.Millisecond))
+	assert.Equal(250*time.Millisecond, b.wait())
+
+	b.refill(now.Add(time.Hour))
+	assert.Equal(float64(3), b.tokens)
+}
+
+func TestPublishRateLimiterFailFast(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.PublishRateLimit = RateLimit{Rate: 0.1, Burst: 2}
+	config.PublishRateLimitMode = PNRateLimitFailFast
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	limiter := pn.publishRateLimiter
+
+	_, err := limiter.acquire(nil, "a", PNPublishOperation)
+	assert.Nil(err)
+	_, err = limiter.acquire(nil, "b", PNSignalOperation)
+	assert.Nil(err)
+
+	status, err := limiter.acquire(nil, "a", PNPublishOperation)
+	assert.Equal(PNTooManyRequestsCategory, status.Category)
+	assert.Equal(PNPublishOperation, status.Operation)
+	var rateErr *pnerr.RateLimitError
+	if assert.True(errors.As(err, &rateErr)) {
+		assert.Equal("a", rateErr.Channel)
+		assert.True(rateErr.Wait > 9*time.Second, rateErr.Wait)
+	}
+	assert.True(pnerr.IsRetryable(err))
+}
+
+func TestPublishRateLimiterPerChannel(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ChannelPublishRateLimit = RateLimit{Rate: 0.1}
+	config.ChannelPublishRateLimits = map[string]RateLimit{"hot": {Rate: 0.1, Burst: 3}}
+	config.PublishRateLimitMode = PNRateLimitFailFast
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	limiter := pn.publishRateLimiter
+
+	_, err := limiter.acquire(nil, "a", PNPublishOperation)
+	assert.Nil(err)
+	_, err = limiter.acquire(nil, "a", PNPublishOperation)
+	assert.NotNil(err)
+	_, err = limiter.acquire(nil, "b", PNPublishOperation)
+	assert.Nil(err)
+
+	for i := 0; i < 3; i++ {
+		_, err = limiter.acquire(nil, "hot", PNPublishOperation)
+		assert.Nil(err)
+	}
+	_, err = limiter.acquire(nil, "hot", PNPublishOperation)
+	assert.NotNil(err)
+}
+
+func TestPublishRateLimiterWait(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.PublishRateLimit = RateLimit{Rate: 10}
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	limiter := pn.publishRateLimiter
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := limiter.acquire(nil, "a", PNPublishOperation)
+		assert.Nil(err)
+	}
+	elapsed := time.Since(start)
+	assert.True(elapsed >= 190*time.Millisecond, elapsed)
+
+	waits := pn.telemetryManager.OperationRateLimitWait()
+	assert.Contains(waits, "w_pub")
+	assert.NotContains(pn.telemetryManager.OperationLatency(), "l_pub")
+}
+
+func TestPublishRateLimiterContext(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.PublishRateLimit = RateLimit{Rate: 0.1}
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	limiter := pn.publishRateLimiter
+
+	_, err := limiter.acquire(nil, "a", PNPublishOperation)
+	assert.Nil(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err := limiter.acquire(ctx, "a", PNPublishOperation)
+	assert.Equal(PNCancelledCategory, status.Category)
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+
+	// the token of the cancelled request is given back
+	assert.True(limiter.instance.tokens > -1, limiter.instance.tokens)
+}
+
+func TestPublishRateLimiterFailFastPublish(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.ChannelPublishRateLimit = RateLimit{Rate: 0.1}
+	config.PublishRateLimitMode = PNRateLimitFailFast
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, `[1,"Sent","15000000000000000"]`, 200))
+
+	_, _, err := pn.Publish().Channel("ch").Message("hi").Execute()
+	assert.Nil(err)
+	_, status, err := pn.Fire().Channel("ch").Message("hi").Execute()
+	assert.NotNil(err)
+	assert.Equal(PNTooManyRequestsCategory, status.Category)
+	_, _, err = pn.Signal().Channel("other").Message("hi").Execute()
+	assert.Nil(err)
+	assert.Equal(2, attempts)
+}
@@ -26,6 +26,7 @@ type ReconnectionManager struct {
 	OnMaxReconnectionExhaustion func()
 	DoneTimer                   chan bool
 	hbRunning                   bool
+	failoverAttempts            int
 	pubnub                      *PubNub
 	exitReconnectionManager     chan bool
 }
@@ -68,6 +69,7 @@ func (m *ReconnectionManager) startPolling() {
 	m.Lock()
 	m.ExponentialMultiplier = 1
 	m.FailedCalls = 0
+	m.failoverAttempts = 0
 	hbRunning := m.hbRunning
 	m.Unlock()
 
@@ -138,6 +140,45 @@ func (m *ReconnectionManager) startHeartbeatTimer() {
 	}
 }
 
+// nextFailoverDelay counts a resubscribe on the next origin and returns the
+// pause before it according to PNReconnectionPolicy. It returns false when
+// the policy is disabled or MaximumReconnectionRetries attempts were made.
+func (m *ReconnectionManager) nextFailoverDelay() (time.Duration, bool) {
+	policy := m.pubnub.Config.PNReconnectionPolicy
+	if policy == PNNonePolicy {
+		return 0, false
+	}
+
+	m.Lock()
+	m.failoverAttempts++
+	attempts := m.failoverAttempts
+	m.Unlock()
+
+	retries := m.pubnub.Config.MaximumReconnectionRetries
+	if retries != -1 && attempts > retries {
+		m.pubnub.Config.Log.Printf("Origin failover retry limit (%d) exceeded", retries)
+		return 0, false
+	}
+
+	interval := reconnectionInterval
+	if policy == PNExponentialPolicy {
+		interval = reconnectionMaxExponentialBackoff
+		if attempts < 6 {
+			interval = 1<<uint(attempts) - 1
+		}
+	}
+	m.pubnub.Config.Log.Printf("Origin failover, reconnection try %d of %d", attempts, retries)
+	return time.Duration(interval) * time.Second, true
+}
+
+// resetFailover clears the failover attempts once a subscribe response was
+// received.
+func (m *ReconnectionManager) resetFailover() {
+	m.Lock()
+	m.failoverAttempts = 0
+	m.Unlock()
+}
+
 func (m *ReconnectionManager) getExponentialInterval() int {
 	timerInterval := int(math.Pow(2, float64(m.ExponentialMultiplier)) - 1)
 	if timerInterval > reconnectionMaxExponentialBackoff {
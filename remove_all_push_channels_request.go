@@ -73,6 +73,13 @@ func (b *removeAllPushChannelsForDeviceBuilder) QueryParam(queryParam map[string
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeAllPushChannelsForDeviceBuilder) RetryPolicy(policy RetryPolicy) *removeAllPushChannelsForDeviceBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the RemoveAllPushNotifications request.
 func (b *removeAllPushChannelsForDeviceBuilder) Execute() (
 	*RemoveAllPushChannelsForDeviceResponse, StatusResponse, error) {
@@ -95,6 +102,8 @@ type removeAllPushChannelsForDeviceOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -195,3 +204,14 @@ func (o *removeAllPushChannelsForDeviceOpts) telemetryManager() *TelemetryManage
 func (o *removeAllPushChannelsForDeviceOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *removeAllPushChannelsForDeviceOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeAllPushChannelsForDeviceOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
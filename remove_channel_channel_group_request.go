@@ -64,6 +64,13 @@ func (b *removeChannelFromChannelGroupBuilder) QueryParam(queryParam map[string]
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeChannelFromChannelGroupBuilder) RetryPolicy(policy RetryPolicy) *removeChannelFromChannelGroupBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs RemoveChannelFromChannelGroup request
 func (b *removeChannelFromChannelGroupBuilder) Execute() (
 	*RemoveChannelFromChannelGroupResponse, StatusResponse, error) {
@@ -84,6 +91,8 @@ type removeChannelOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -175,6 +184,17 @@ func (o *removeChannelOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *removeChannelOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeChannelOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // RemoveChannelFromChannelGroupResponse is the struct returned when the Execute function of RemoveChannelFromChannelGroup is called.
 type RemoveChannelFromChannelGroupResponse struct {
 }
@@ -79,6 +79,13 @@ func (b *removeChannelsFromPushBuilder) QueryParam(queryParam map[string]string)
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *removeChannelsFromPushBuilder) RetryPolicy(policy RetryPolicy) *removeChannelsFromPushBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the RemovePushNotificationsFromChannels request.
 func (b *removeChannelsFromPushBuilder) Execute() (*RemoveChannelsFromPushResponse, StatusResponse, error) {
 	_, status, err := executeRequest(b.opts)
@@ -101,6 +108,8 @@ type removeChannelsFromPushOpts struct {
 
 	Transport http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -211,3 +220,14 @@ func (o *removeChannelsFromPushOpts) telemetryManager() *TelemetryManager {
 func (o *removeChannelsFromPushOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *removeChannelsFromPushOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *removeChannelsFromPushOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
@@ -2,6 +2,8 @@ package pubnub
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,6 +30,7 @@ type StatusResponse struct {
 	AffectedChannels      []string
 	AffectedChannelGroups []string
 	AdditionalData        interface{}
+	Retries               int // Number of times the request was retried, see RetryPolicy.
 }
 
 // ResponseInfo is used to store the properties in the response of an request.
@@ -76,6 +79,23 @@ func buildBody(opts endpointOpts, url *url.URL) (io.Reader, error) {
 }
 
 func executeRequest(opts endpointOpts) ([]byte, StatusResponse, error) {
+	policy := opts.retryPolicy()
+	for retries := 0; ; retries++ {
+		val, status, err := executeRequestAttempt(opts)
+		status.Retries = retries
+		if err == nil || !policy.shouldRetry(opts.operationType(), retries, err) {
+			return val, status, err
+		}
+
+		delay := policy.delay(retries, err)
+		opts.config().Log.Println(fmt.Sprintf("retry %d of %s in %s: %s", retries+1, opts.operationType(), delay, err))
+		if !waitRetry(opts.context(), delay) {
+			return val, status, err
+		}
+	}
+}
+
+func executeRequestAttempt(opts endpointOpts) ([]byte, StatusResponse, error) {
 	err := opts.validate()
 
 	if err != nil {
@@ -107,18 +127,20 @@ func executeRequest(opts endpointOpts) ([]byte, StatusResponse, error) {
 		req.Header.Set("Content-Type", "application/json")
 	} else if opts.httpMethod() == "POSTFORM" {
 
-		body, w, _, err := opts.buildBodyMultipartFileUpload()
+		body, contentType, length, err := multipartBody(opts)
 		if err != nil {
 			return nil, createStatus(PNUnknownCategory, "", ResponseInfo{}, err), err
 		}
 
-		req, err = newRequestForMultipartWriter("POST", url.RequestURI(), &body, w, opts.config().UseHTTP2)
+		req, err = newRequestForMultipartWriter("POST", url.RequestURI(), body, nil, opts.config().UseHTTP2)
 		if err != nil {
+			body.Close()
 			opts.config().Log.Println("POST ERROR : ", err)
 			return nil, createStatus(PNUnknownCategory, "", ResponseInfo{}, err), err
 		}
 
-		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.ContentLength = length
+		req.Header.Set("Content-Type", contentType)
 	} else if opts.httpMethod() == "DELETE" {
 		req, err = newRequest("DELETE", url, nil, opts.config().UseHTTP2)
 	} else if opts.httpMethod() == "PATCH" {
@@ -174,16 +196,23 @@ func executeRequest(opts endpointOpts) ([]byte, StatusResponse, error) {
 	if err != nil {
 		opts.config().Log.Println("err.Error()", err.Error())
 		e := pnerr.NewConnectionError("Failed to execute request", err)
+		category := categoryForError(e)
+		if category != PNCancelledCategory {
+			opts.originManager().reportFailure(url.Host)
+		}
 
-		opts.config().Log.Println("PNUnknownCategory", e.Error(), url)
+		opts.config().Log.Println(category, e.Error(), url)
 		return nil,
-			createStatus(PNUnknownCategory, "", ResponseInfo{}, e),
+			createStatus(category, "", ResponseInfo{Operation: opts.operationType(), Origin: url.Host}, e),
 			e
 	}
 
+	opts.originManager().reportSuccess(url.Host)
+
 	val, status, err := parseResponse(res, opts)
 	// Already wrapped error
 	if err != nil {
+		status.Origin = url.Host
 		opts.config().Log.Println("res.StatusCode, status, err.Error()", res.StatusCode, status, err.Error())
 		return nil, status, err
 	}
@@ -220,6 +249,26 @@ func executeRequest(opts endpointOpts) ([]byte, StatusResponse, error) {
 	return val, status, nil
 }
 
+// multipartBodyStreamer is implemented by the endpoints streaming their
+// multipart body instead of building it with buildBodyMultipartFileUpload.
+type multipartBodyStreamer interface {
+	streamBodyMultipartFileUpload() (body io.ReadCloser, contentType string, length int64, err error)
+}
+
+// multipartBody returns the body of a POSTFORM request, its content type and
+// its length.
+func multipartBody(opts endpointOpts) (io.ReadCloser, string, int64, error) {
+	if streamer, ok := opts.(multipartBodyStreamer); ok {
+		return streamer.streamBodyMultipartFileUpload()
+	}
+
+	body, w, _, err := opts.buildBodyMultipartFileUpload()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return ioutil.NopCloser(&body), w.FormDataContentType(), int64(body.Len()), nil
+}
+
 func newRequestForMultipartWriter(method string, URL string, body io.Reader, writer *multipart.Writer, useHTTP2 bool) (*http.Request, error) {
 	req, err := http.NewRequest(method, URL, body)
 	if useHTTP2 {
@@ -275,26 +324,14 @@ func parseResponse(resp *http.Response, opts endpointOpts) ([]byte, StatusRespon
 	status := StatusResponse{}
 
 	if (resp.StatusCode != 200) && (resp.StatusCode != 204) {
-		// Errors like 400, 403, 500
+		// Errors like 400, 403, 429, 500
 		e := pnerr.NewServerError(resp.StatusCode, resp.Body)
+		e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		category := categoryForError(e)
 
 		opts.config().Log.Println(e.Error())
-
-		if resp.StatusCode == 408 {
-			opts.config().Log.Println("PNTimeoutCategory: resp.StatusCode, resp.Body, resp.Request.URL", resp.StatusCode, resp.Body, resp.Request.URL)
-			status = createStatus(PNTimeoutCategory, "", ResponseInfo{StatusCode: resp.StatusCode}, e)
-
-			return nil, status, e
-		}
-
-		if resp.StatusCode == 400 {
-			opts.config().Log.Println("PNBadRequestCategory: resp.StatusCode, resp.Body, resp.Request.URL", resp.StatusCode, resp.Body, resp.Request.URL)
-			status = createStatus(PNBadRequestCategory, "", ResponseInfo{StatusCode: resp.StatusCode}, e)
-
-			return nil, status, e
-		}
-		opts.config().Log.Println("PNUnknownCategory: resp.StatusCode, resp.Body, resp.Request.URL", resp.StatusCode, resp.Body, resp.Request.URL)
-		status = createStatus(PNUnknownCategory, "", ResponseInfo{StatusCode: resp.StatusCode, Operation: opts.operationType()}, e)
+		opts.config().Log.Println("resp.StatusCode, category, resp.Request.URL", resp.StatusCode, category, resp.Request.URL)
+		status = createStatus(category, "", ResponseInfo{StatusCode: resp.StatusCode, Operation: opts.operationType()}, e)
 
 		return nil, status, e
 	}
@@ -303,6 +340,7 @@ func parseResponse(resp *http.Response, opts endpointOpts) ([]byte, StatusRespon
 	if err != nil {
 		e := pnerr.NewResponseParsingError("Error reading response body", resp.Body, err)
 		opts.config().Log.Println("Read All error: resp.Body, resp.Request.URL, e", resp.StatusCode, resp.Body, resp.Request.URL, e)
+		status = createStatus(PNMalformedResponseCategory, "", ResponseInfo{StatusCode: resp.StatusCode, Operation: opts.operationType()}, e)
 
 		return nil, status, e
 	}
@@ -313,6 +351,47 @@ func parseResponse(resp *http.Response, opts endpointOpts) ([]byte, StatusRespon
 	return body, status, nil
 }
 
+// categoryForError maps the errors returned by executeRequest to a StatusCategory.
+func categoryForError(err error) StatusCategory {
+	var serverErr *pnerr.ServerError
+	var connErr *pnerr.ConnectionError
+	var parsingErr *pnerr.ResponseParsingError
+
+	switch {
+	case errors.As(err, &serverErr):
+		switch code := serverErr.StatusCode; {
+		case code == 400:
+			return PNBadRequestCategory
+		case code == 403:
+			return PNAccessDeniedCategory
+		case code == 408:
+			return PNTimeoutCategory
+		case code == 429:
+			return PNTooManyRequestsCategory
+		case code == 530:
+			return PNNoStubMatchedCategory
+		case code >= 500:
+			return PNServerErrorCategory
+		}
+	case errors.As(err, &connErr):
+		switch {
+		case errors.Is(connErr, context.Canceled):
+			return PNCancelledCategory
+		case connErr.IsTimeout():
+			return PNTimeoutCategory
+		case connErr.IsDNSError():
+			return PNDNSFailureCategory
+		case errors.As(connErr, new(*pnerr.PinningError)):
+			return PNTLSPinningFailureCategory
+		case pnerr.IsTLSError(connErr):
+			return PNTLSFailureCategory
+		}
+	case errors.As(err, &parsingErr):
+		return PNMalformedResponseCategory
+	}
+	return PNUnknownCategory
+}
+
 func createStatus(category StatusCategory, response string,
 	responseInfo ResponseInfo, err error) StatusResponse {
 	resp := StatusResponse{}
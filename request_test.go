@@ -0,0 +1,123 @@
+package pubnub
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestCategoryForServerError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(PNBadRequestCategory, categoryForError(&pnerr.ServerError{StatusCode: 400}))
+	assert.Equal(PNAccessDeniedCategory, categoryForError(&pnerr.ServerError{StatusCode: 403}))
+	assert.Equal(PNTimeoutCategory, categoryForError(&pnerr.ServerError{StatusCode: 408}))
+	assert.Equal(PNTooManyRequestsCategory, categoryForError(&pnerr.ServerError{StatusCode: 429}))
+	assert.Equal(PNServerErrorCategory, categoryForError(&pnerr.ServerError{StatusCode: 502}))
+	assert.Equal(PNNoStubMatchedCategory, categoryForError(&pnerr.ServerError{StatusCode: 530}))
+	assert.Equal(PNUnknownCategory, categoryForError(&pnerr.ServerError{StatusCode: 404}))
+}
+
+func TestCategoryForConnectionError(t *testing.T) {
+	assert := assert.New(t)
+
+	dnsErr := pnerr.NewConnectionError("Failed to execute request",
+		&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "ps.pndsn.com"}})
+	assert.Equal(PNDNSFailureCategory, categoryForError(dnsErr))
+	assert.False(dnsErr.Retryable())
+
+	dnsTimeoutErr := pnerr.NewConnectionError("Failed to execute request",
+		&net.OpError{Op: "dial", Err: &net.DNSError{Err: "i/o timeout", Name: "ps.pndsn.com", IsTimeout: true}})
+	assert.Equal(PNTimeoutCategory, categoryForError(dnsTimeoutErr))
+	assert.True(dnsTimeoutErr.Retryable())
+
+	tlsErr := pnerr.NewConnectionError("Failed to execute request", x509.UnknownAuthorityError{})
+	assert.Equal(PNTLSFailureCategory, categoryForError(tlsErr))
+	assert.False(tlsErr.Retryable())
+
+	pinErr := pnerr.NewConnectionError("Failed to execute request",
+		&url.Error{Op: "Get", URL: "https://ps.pndsn.com/time/0", Err: pnerr.NewPinningError("ps.pndsn.com", nil)})
+	assert.Equal(PNTLSPinningFailureCategory, categoryForError(pinErr))
+	assert.False(pinErr.Retryable())
+
+	timeoutErr := pnerr.NewConnectionError("Failed to execute request", timeoutError{})
+	assert.Equal(PNTimeoutCategory, categoryForError(timeoutErr))
+
+	cancelErr := pnerr.NewConnectionError("Failed to execute request", context.Canceled)
+	assert.Equal(PNCancelledCategory, categoryForError(cancelErr))
+	assert.False(pnerr.IsRetryable(cancelErr))
+	assert.True(errors.Is(cancelErr, context.Canceled))
+}
+
+func TestCategoryForTLSHandshakeAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{MaxVersion: tls.VersionTLS12, InsecureSkipVerify: true},
+	}}
+	_, err := client.Get(server.URL)
+	assert.NotNil(err)
+
+	e := pnerr.NewConnectionError("Failed to execute request", err)
+	assert.True(pnerr.IsTLSError(err))
+	assert.Equal(PNTLSFailureCategory, categoryForError(e))
+	assert.False(e.Retryable())
+
+	remoteAlert := &net.OpError{Op: "remote error", Err: errors.New("tls: handshake failure")}
+	assert.True(pnerr.IsTLSError(remoteAlert))
+	assert.False(pnerr.IsTLSError(&net.OpError{Op: "remote error", Err: errors.New("connection reset")}))
+}
+
+func TestCategoryForParsingError(t *testing.T) {
+	assert := assert.New(t)
+
+	origErr := errors.New("unexpected end of JSON input")
+	e := pnerr.NewResponseParsingError("Error unmarshalling response", nil, origErr)
+
+	assert.Equal(PNMalformedResponseCategory, categoryForError(e))
+	assert.True(errors.Is(e, origErr))
+	assert.False(pnerr.IsRetryable(e))
+}
+
+func TestServerErrorIsAndRetryable(t *testing.T) {
+	assert := assert.New(t)
+
+	var err error = &pnerr.ServerError{StatusCode: 429}
+
+	assert.True(errors.Is(err, &pnerr.ServerError{StatusCode: 429}))
+	assert.True(errors.Is(err, &pnerr.ServerError{}))
+	assert.False(errors.Is(err, &pnerr.ServerError{StatusCode: 500}))
+	assert.True(pnerr.IsRetryable(err))
+	assert.False(pnerr.IsRetryable(&pnerr.ServerError{StatusCode: 400}))
+	assert.False(pnerr.IsRetryable(&pnerr.ServerError{StatusCode: 530}))
+	assert.True(pnerr.IsRetryable(&pnerr.ServerError{StatusCode: 503}))
+	assert.False(pnerr.IsRetryable(pnerr.NewValidationError("publish", StrMissingChannel)))
+	assert.False(pnerr.IsRetryable(errors.New("plain")))
+
+	var serverErr *pnerr.ServerError
+	assert.True(errors.As(err, &serverErr))
+	assert.Equal(429, serverErr.StatusCode)
+}
@@ -0,0 +1,124 @@
+package pubnub
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+)
+
+// RetryPolicy configures the retries of the non-subscribe requests after
+// transient failures: connection errors, timeouts, 429 and 5xx responses. The
+// subscribe requests are repeated by the ReconnectionManager instead.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one, 0 or 1
+	// disables the retries.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry, doubled for each
+	// following one.
+	InitialDelay time.Duration
+	// MaxDelay bounds the delay between two attempts, 0 means no bound. A
+	// request rate limited for longer than MaxDelay isn't retried.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay randomized, e.g. 0.2 waits between
+	// 80% and 120% of it.
+	Jitter float64
+	// RetryNonIdempotent retries the operations applied twice when only the
+	// response was lost, e.g. Publish or AddMessageAction, too.
+	RetryNonIdempotent bool
+}
+
+// shouldRetry reports whether the request of operation which failed with err
+// after retries retries is attempted again.
+func (p RetryPolicy) shouldRetry(operation OperationType, retries int, err error) bool {
+	if retries+1 >= p.MaxAttempts || !pnerr.IsRetryable(err) {
+		return false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(operation) {
+		return false
+	}
+
+	var serverErr *pnerr.ServerError
+	if errors.As(err, &serverErr) && p.MaxDelay > 0 && serverErr.RetryAfter > p.MaxDelay {
+		return false
+	}
+	return true
+}
+
+// delay returns the delay before the retry following retries retries, the
+// Retry-After of a rate limited request when the server asked for one.
+func (p RetryPolicy) delay(retries int, err error) time.Duration {
+	var serverErr *pnerr.ServerError
+	if errors.As(err, &serverErr) && serverErr.RetryAfter > 0 {
+		return serverErr.RetryAfter
+	}
+
+	delay := p.InitialDelay
+	for i := 0; i < retries && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		if delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// isIdempotent reports whether running operation twice has the same effect
+// as running it once.
+func isIdempotent(operation OperationType) bool {
+	switch operation {
+	case PNPublishOperation, PNFireOperation, PNSignalOperation,
+		PNPublishFileMessageOperation, PNSendFileOperation,
+		PNAddMessageActionsOperation, PNAccessManagerGrantToken:
+		return false
+	}
+	return true
+}
+
+// waitRetry waits for delay, it returns false if ctx is done first.
+func waitRetry(ctx Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds or
+// an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package pubnub
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	assert := assert.New(t)
+	policy := RetryPolicy{
+		MaxAttempts:  10,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+	}
+	serverErr := &pnerr.ServerError{StatusCode: 503}
+
+	assert.Equal(100*time.Millisecond, policy.delay(0, serverErr))
+	assert.Equal(200*time.Millisecond, policy.delay(1, serverErr))
+	assert.Equal(800*time.Millisecond, policy.delay(3, serverErr))
+	assert.Equal(time.Second, policy.delay(4, serverErr))
+	assert.Equal(time.Second, policy.delay(100, serverErr))
+
+	assert.Equal(3*time.Second, policy.delay(0, &pnerr.ServerError{StatusCode: 429, RetryAfter: 3 * time.Second}))
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(1, serverErr)
+		assert.True(delay >= 160*time.Millisecond && delay <= 240*time.Millisecond, delay)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	assert := assert.New(t)
+	policy := RetryPolicy{MaxAttempts: 3}
+	serverErr := &pnerr.ServerError{StatusCode: 503}
+
+	assert.True(policy.shouldRetry(PNTimeOperation, 0, serverErr))
+	assert.True(policy.shouldRetry(PNTimeOperation, 1, serverErr))
+	assert.False(policy.shouldRetry(PNTimeOperation, 2, serverErr))
+	assert.False(policy.shouldRetry(PNTimeOperation, 0, &pnerr.ServerError{StatusCode: 400}))
+	assert.False(policy.shouldRetry(PNTimeOperation, 0, pnerr.NewConnectionError("Failed to execute request", context.Canceled)))
+	assert.False(policy.shouldRetry(PNPublishOperation, 0, serverErr))
+	assert.False(RetryPolicy{}.shouldRetry(PNTimeOperation, 0, serverErr))
+
+	policy.RetryNonIdempotent = true
+	assert.True(policy.shouldRetry(PNPublishOperation, 0, serverErr))
+
+	policy.MaxDelay = time.Second
+	assert.False(policy.shouldRetry(PNTimeOperation, 0, &pnerr.ServerError{StatusCode: 429, RetryAfter: time.Minute}))
+	assert.True(policy.shouldRetry(PNTimeOperation, 0, &pnerr.ServerError{StatusCode: 429, RetryAfter: time.Second}))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(time.Duration(0), parseRetryAfter("-1", now))
+	assert.Equal(90*time.Second, parseRetryAfter("Wed, 01 Jan 2020 00:01:30 GMT", now))
+	assert.Equal(time.Duration(0), parseRetryAfter("Tue, 31 Dec 2019 00:00:00 GMT", now))
+	assert.Equal(time.Duration(0), parseRetryAfter("soon", now))
+}
+
+// newRetryClient returns a client answering the status codes of responses in
+// turn, the last one repeatedly, with body when it's 200.
+func newRetryClient(attempts *int, body string, responses ...int) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			code := responses[len(responses)-1]
+			if *attempts < len(responses) {
+				code = responses[*attempts]
+			}
+			*attempts++
+
+			content := `{"error": true}`
+			if code == 200 {
+				content = body
+			}
+			header := http.Header{}
+			if code == 429 {
+				header.Set("Retry-After", "0")
+			}
+			return &http.Response{
+				StatusCode: code,
+				Header:     header,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(content)),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+func TestExecuteRequestRetries(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	pn := NewPubNub(config)
+
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, `[15000000000000000]`, 503, 429, 200))
+	res, status, err := pn.Time().Execute()
+	assert.Nil(err)
+	assert.Equal(3, attempts)
+	assert.Equal(2, status.Retries)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timetoken)
+	}
+
+	attempts = 0
+	pn.SetClient(newRetryClient(&attempts, `[15000000000000000]`, 502))
+	_, status, err = pn.Time().Execute()
+	assert.NotNil(err)
+	assert.Equal(3, attempts)
+	assert.Equal(2, status.Retries)
+	assert.Equal(502, status.StatusCode)
+
+	// the override of the builder applies to this request only
+	attempts = 0
+	_, status, err = pn.Time().RetryPolicy(RetryPolicy{}).Execute()
+	assert.NotNil(err)
+	assert.Equal(1, attempts)
+	assert.Equal(0, status.Retries)
+}
+
+func TestExecuteRequestRetriesNonIdempotent(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	pn := NewPubNub(config)
+
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, `[1,"Sent","15000000000000000"]`, 503, 200))
+	_, status, err := pn.Publish().Channel("ch").Message("hi").Execute()
+	assert.NotNil(err)
+	assert.Equal(1, attempts)
+	assert.Equal(0, status.Retries)
+
+	attempts = 0
+	res, status, err := pn.Publish().Channel("ch").Message("hi").
+		RetryPolicy(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryNonIdempotent: true}).
+		Execute()
+	assert.Nil(err)
+	assert.Equal(2, attempts)
+	assert.Equal(1, status.Retries)
+	if assert.NotNil(res) {
+		assert.Equal(int64(15000000000000000), res.Timestamp)
+	}
+}
+
+func TestExecuteRequestRetryCancelled(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Minute}
+	pn := NewPubNub(config)
+
+	attempts := 0
+	pn.SetClient(newRetryClient(&attempts, `[15000000000000000]`, 503))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, status, err := pn.TimeWithContext(ctx).Execute()
+	assert.NotNil(err)
+	assert.Equal(1, attempts)
+	assert.Equal(0, status.Retries)
+	assert.True(time.Since(start) < 10*time.Second)
+}
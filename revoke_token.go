@@ -56,6 +56,13 @@ func (b *revokeTokenBuilder) QueryParam(queryParam map[string]string) *revokeTok
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *revokeTokenBuilder) RetryPolicy(policy RetryPolicy) *revokeTokenBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Grant request.
 func (b *revokeTokenBuilder) Execute() (*PNRevokeTokenResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -70,8 +77,9 @@ type revokeTokenOpts struct {
 	pubnub *PubNub
 	ctx    Context
 
-	QueryParam map[string]string
-	Token      string
+	QueryParam  map[string]string
+	RetryPolicy *RetryPolicy
+	Token       string
 }
 
 func (o *revokeTokenOpts) config() Config {
@@ -157,6 +165,17 @@ func (o *revokeTokenOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *revokeTokenOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *revokeTokenOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // PNRevokeTokenResponse is the struct returned when the Execute function of Grant Token is called.
 type PNRevokeTokenResponse struct {
 	status int `json:"status"`
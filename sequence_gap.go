@@ -0,0 +1,85 @@
+package pubnub
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxTrackedPublishers bounds the number of publishers whose sequence
+// numbers are remembered, the tracking starts over once it's reached.
+const maxTrackedPublishers = 10000
+
+// PNSequenceGapError is the ErrorData of a PNSequenceGapCategory status. It
+// describes the sequence numbers of a publisher that weren't received.
+type PNSequenceGapError struct {
+	Publisher string
+	Channel   string
+	Expected  int // The sequence number following the last one received from Publisher.
+	Received  int
+}
+
+func (e *PNSequenceGapError) Error() string {
+	return fmt.Sprintf("sequence gap from %s on %s: expected %d, received %d",
+		e.Publisher, e.Channel, e.Expected, e.Received)
+}
+
+// Missed returns the number of messages skipped by the gap.
+func (e *PNSequenceGapError) Missed() int {
+	if e.Received >= e.Expected {
+		return e.Received - e.Expected
+	}
+	// the sequence wrapped around MaxSequence
+	return MaxSequence - e.Expected + e.Received
+}
+
+// sequenceTracker remembers the last sequence number received from each
+// publisher to detect the messages lost in between.
+type sequenceTracker struct {
+	sync.Mutex
+
+	last map[string]int
+}
+
+func newSequenceTracker() *sequenceTracker {
+	return &sequenceTracker{
+		last: make(map[string]int),
+	}
+}
+
+// track records the sequence number of a message of publisher and returns a
+// gap if the previous sequence number received from it isn't the preceding
+// one. A lower sequence number than the last one means the publisher was
+// restarted, unless it's a wrap around MaxSequence, and isn't reported.
+func (t *sequenceTracker) track(publisher, channel string, sequence int) *PNSequenceGapError {
+	if publisher == "" || sequence <= 0 {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	last, ok := t.last[publisher]
+	if !ok && len(t.last) >= maxTrackedPublishers {
+		t.last = make(map[string]int)
+	}
+	t.last[publisher] = sequence
+	if !ok || last == sequence {
+		return nil
+	}
+
+	expected := last + 1
+	if last >= MaxSequence {
+		expected = 1
+	}
+	wrapped := sequence < last && last-sequence > MaxSequence/2
+	if sequence == expected || (sequence < last && !wrapped) {
+		return nil
+	}
+
+	return &PNSequenceGapError{
+		Publisher: publisher,
+		Channel:   channel,
+		Expected:  expected,
+		Received:  sequence,
+	}
+}
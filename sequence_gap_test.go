@@ -0,0 +1,61 @@
+package pubnub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceTrackerConsecutive(t *testing.T) {
+	assert := assert.New(t)
+	tracker := newSequenceTracker()
+
+	assert.Nil(tracker.track("a", "ch", 1))
+	assert.Nil(tracker.track("a", "ch", 2))
+	assert.Nil(tracker.track("b", "ch", 7))
+	assert.Nil(tracker.track("a", "ch", 3))
+	// duplicate
+	assert.Nil(tracker.track("a", "ch", 3))
+	// no sequence number
+	assert.Nil(tracker.track("a", "ch", 0))
+}
+
+func TestSequenceTrackerGap(t *testing.T) {
+	assert := assert.New(t)
+	tracker := newSequenceTracker()
+
+	assert.Nil(tracker.track("a", "ch", 1))
+	gap := tracker.track("a", "ch", 4)
+	if assert.NotNil(gap) {
+		assert.Equal("a", gap.Publisher)
+		assert.Equal("ch", gap.Channel)
+		assert.Equal(2, gap.Expected)
+		assert.Equal(4, gap.Received)
+		assert.Equal(2, gap.Missed())
+	}
+	assert.Nil(tracker.track("a", "ch", 5))
+}
+
+func TestSequenceTrackerRestartedPublisher(t *testing.T) {
+	assert := assert.New(t)
+	tracker := newSequenceTracker()
+
+	assert.Nil(tracker.track("a", "ch", 100))
+	assert.Nil(tracker.track("a", "ch", 1))
+	assert.Nil(tracker.track("a", "ch", 2))
+}
+
+func TestSequenceTrackerWrapAround(t *testing.T) {
+	assert := assert.New(t)
+	tracker := newSequenceTracker()
+
+	assert.Nil(tracker.track("a", "ch", MaxSequence))
+	assert.Nil(tracker.track("a", "ch", 1))
+
+	assert.Nil(tracker.track("b", "ch", MaxSequence-1))
+	gap := tracker.track("b", "ch", 2)
+	if assert.NotNil(gap) {
+		assert.Equal(MaxSequence, gap.Expected)
+		assert.Equal(2, gap.Missed())
+	}
+}
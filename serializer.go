@@ -0,0 +1,89 @@
+package pubnub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/pubnub/go/v7/utils"
+)
+
+// Serializer encodes the messages, meta and state sent, and decodes the
+// messages received. The received messages are decoded into an
+// *interface{}, a Serializer can store its own types in it.
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONSerializer is the default Serializer, it uses encoding/json.
+type JSONSerializer struct {
+	// UseNumber decodes the numbers as json.Number instead of float64, it
+	// keeps the precision of the large integers.
+	UseNumber bool
+}
+
+// Marshal returns the JSON encoding of v.
+func (s JSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the JSON in data into v.
+func (s JSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	if !s.UseNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+func (c *Config) serializer() Serializer {
+	if c.Serializer == nil {
+		return JSONSerializer{}
+	}
+	return c.Serializer
+}
+
+// valueAsString is utils.ValueAsString with serializer, the strings are
+// quoted as is.
+func valueAsString(serializer Serializer, value interface{}) ([]byte, error) {
+	if _, ok := value.(string); ok {
+		return utils.ValueAsString(value)
+	}
+	return serializer.Marshal(value)
+}
+
+// decodePayload decodes a received payload with Config.Serializer. value is
+// the payload as decoded by encoding/json, it's kept with the default
+// Serializer or when raw is missing.
+func decodePayload(raw []byte, value interface{}, pnConf *Config) (interface{}, error) {
+	if len(raw) == 0 || pnConf.Serializer == nil {
+		return value, nil
+	}
+
+	var decoded interface{}
+	if err := pnConf.Serializer.Unmarshal(raw, &decoded); err != nil {
+		pnConf.Log.Println("Unmarshal: err", err)
+		return value, err
+	}
+	return decoded, nil
+}
+
+// decodeMessage decodes a received message like decodePayload and decrypts it
+// when a CipherKey is set.
+func decodeMessage(raw []byte, value interface{}, pnConf *Config) (interface{}, error) {
+	decoded, err := decodePayload(raw, value, pnConf)
+	if err != nil {
+		return decoded, err
+	}
+	return parseCipherInterface(decoded, pnConf)
+}
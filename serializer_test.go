@@ -0,0 +1,171 @@
+package pubnub
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type point struct {
+	X, Y int
+}
+
+// pointSerializer encodes point as [x,y] and decodes the two number arrays
+// into point.
+type pointSerializer struct {
+	JSONSerializer
+}
+
+func (s pointSerializer) Marshal(v interface{}) ([]byte, error) {
+	if p, ok := v.(point); ok {
+		return []byte(fmt.Sprintf("[%d,%d]", p.X, p.Y)), nil
+	}
+	return s.JSONSerializer.Marshal(v)
+}
+
+func (s pointSerializer) Unmarshal(data []byte, v interface{}) error {
+	var p [2]int
+	if err := json.Unmarshal(data, &p); err == nil {
+		*(v.(*interface{})) = point{X: p[0], Y: p[1]}
+		return nil
+	}
+	return s.JSONSerializer.Unmarshal(data, v)
+}
+
+func TestJSONSerializerUseNumber(t *testing.T) {
+	assert := assert.New(t)
+	data := []byte(`{"id":12345678901234567890}`)
+
+	var value interface{}
+	assert.Nil(JSONSerializer{}.Unmarshal(data, &value))
+	assert.Equal(float64(12345678901234567890), value.(map[string]interface{})["id"])
+
+	assert.Nil(JSONSerializer{UseNumber: true}.Unmarshal(data, &value))
+	assert.Equal(json.Number("12345678901234567890"), value.(map[string]interface{})["id"])
+
+	assert.NotNil(JSONSerializer{UseNumber: true}.Unmarshal([]byte(`{} {}`), &value))
+}
+
+func TestSerializerOutgoing(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Serializer = pointSerializer{}
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	publish := pn.Publish().Channel("ch").Message(point{1, 2}).Meta(point{3, 4})
+	assert.Nil(publish.opts.validate())
+	path, err := publish.opts.buildPath()
+	assert.Nil(err)
+	assert.Equal("/publish/demo/demo/0/ch/0/%5B1%2C2%5D", path)
+	query, err := publish.opts.buildQuery()
+	assert.Nil(err)
+	assert.Equal("[3,4]", query.Get("meta"))
+
+	signal := pn.Signal().Channel("ch").Message(point{5, 6})
+	path, err = signal.opts.buildPath()
+	assert.Nil(err)
+	assert.Equal("/signal/demo/demo/0/ch/0/%5B5%2C6%5D", path)
+
+	fire := pn.Fire().Channel("ch").Message(point{7, 8}).UsePost(true)
+	body, err := fire.opts.buildBody()
+	assert.Nil(err)
+	assert.Equal("[7,8]", string(body))
+
+	// the strings are still sent as is
+	publish = pn.Publish().Channel("ch").Message("hi").Meta("meta")
+	assert.Nil(publish.opts.validate())
+	path, err = publish.opts.buildPath()
+	assert.Nil(err)
+	assert.Equal("/publish/demo/demo/0/ch/0/%22hi%22", path)
+}
+
+func TestSerializerPublishEncrypted(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Serializer = pointSerializer{}
+	config.CipherKey = "enigma"
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+
+	publish := pn.Publish().Channel("ch").Message(point{1, 2})
+	msg, err := publish.opts.message()
+	assert.Nil(err)
+
+	var encrypted interface{}
+	assert.Nil(json.Unmarshal([]byte(msg), &encrypted))
+	decrypted, err := parseCipherInterface(encrypted, pn.Config)
+	assert.Nil(err)
+	assert.Equal(point{1, 2}, decrypted)
+}
+
+func TestSerializerSubscribe(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.Serializer = JSONSerializer{UseNumber: true}
+	pn := NewPubNub(config)
+	defer pn.Destroy()
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	var envelope subscribeEnvelope
+	assert.Nil(json.Unmarshal([]byte(`{"t":{"t":"15000000000000001","r":1},"m":[
+		{"c":"ch","i":"a","d":{"id":12345678901234567890},"p":{"t":"15000000000000000"}},
+		{"c":"ch","i":"a","e":1,"d":42,"p":{"t":"15000000000000001"}}]}`), &envelope))
+	assert.Equal(2, len(envelope.Messages))
+	assert.Equal(float64(42), envelope.Messages[1].Payload)
+
+	for _, message := range envelope.Messages {
+		processSubscribePayload(pn.subscriptionManager, message)
+	}
+
+	select {
+	case m := <-listener.Message:
+		assert.Equal(map[string]interface{}{"id": json.Number("12345678901234567890")}, m.Message)
+	case <-time.After(2 * time.Second):
+		assert.Fail("message not delivered")
+	}
+	select {
+	case m := <-listener.Signal:
+		assert.Equal(json.Number("42"), m.Message)
+	case <-time.After(2 * time.Second):
+		assert.Fail("signal not delivered")
+	}
+}
+
+func TestSerializerFetchAndHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	fetch := initFetchOpts("")
+	fetch.pubnub.Config.Serializer = pointSerializer{}
+	resp, _, err := newFetchResponse([]byte(`{"status": 200, "channels": {"ch":[{"message":[1,2],"timetoken":"15000000000000000"},{"message":{"id":1},"timetoken":"15000000000000001"}]}}`), fetch, fakeResponseState)
+	assert.Nil(err)
+	if assert.Equal(2, len(resp.Messages["ch"])) {
+		assert.Equal(point{1, 2}, resp.Messages["ch"][0].Message)
+		assert.Equal(map[string]interface{}{"id": float64(1)}, resp.Messages["ch"][1].Message)
+	}
+
+	// initHistoryOpts shares its PubNub with the other tests
+	config := NewDemoConfig()
+	config.Serializer = pointSerializer{}
+	history := initHistoryOpts()
+	history.pubnub = NewPubNub(config)
+	historyResp, _, err := newHistoryResponse([]byte(`[[[3,4],"hi"],15000000000000000,15000000000000001]`), history, fakeResponseState)
+	assert.Nil(err)
+	if assert.Equal(2, len(historyResp.Messages)) {
+		assert.Equal(point{3, 4}, historyResp.Messages[0].Message)
+		assert.Equal("hi", historyResp.Messages[1].Message)
+	}
+
+	history = initHistoryOpts()
+	history.pubnub = NewPubNub(config)
+	historyResp, _, err = newHistoryResponse([]byte(`[[{"message":[5,6],"timetoken":15000000000000000}],15000000000000000,15000000000000000]`), history, fakeResponseState)
+	assert.Nil(err)
+	if assert.Equal(1, len(historyResp.Messages)) {
+		assert.Equal(point{5, 6}, historyResp.Messages[0].Message)
+		assert.Equal(int64(15000000000000000), historyResp.Messages[0].Timetoken)
+	}
+}
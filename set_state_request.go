@@ -68,6 +68,13 @@ func (b *setStateBuilder) QueryParam(queryParam map[string]string) *setStateBuil
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *setStateBuilder) RetryPolicy(policy RetryPolicy) *setStateBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // UUID sets the UUID for the Set State request.
 func (b *setStateBuilder) UUID(uuid string) *setStateBuilder {
 	b.opts.UUID = uuid
@@ -98,6 +105,7 @@ type setStateOpts struct {
 	ChannelGroups []string
 	UUID          string
 	QueryParam    map[string]string
+	RetryPolicy   *RetryPolicy
 	pubnub        *PubNub
 	stringState   string
 	ctx           Context
@@ -127,7 +135,7 @@ func (o *setStateOpts) validate() error {
 	if o.State == nil {
 		return newValidationError(o, "Missing State")
 	}
-	state, err := json.Marshal(o.State)
+	state, err := o.pubnub.Config.serializer().Marshal(o.State)
 	if err != nil {
 		return newValidationError(o, err.Error())
 	}
@@ -209,6 +217,17 @@ func (o *setStateOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *setStateOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *setStateOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 func newSetStateResponse(jsonBytes []byte, status StatusResponse) (
 	*SetStateResponse, StatusResponse, error) {
 	resp := &SetStateResponse{}
@@ -81,8 +81,19 @@ func (b *signalBuilder) QueryParam(queryParam map[string]string) *signalBuilder
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *signalBuilder) RetryPolicy(policy RetryPolicy) *signalBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Signal request.
 func (b *signalBuilder) Execute() (*SignalResponse, StatusResponse, error) {
+	if status, err := b.opts.pubnub.publishRateLimiter.acquire(b.opts.ctx, b.opts.Channel, PNSignalOperation); err != nil {
+		return emptySignalResponse, status, err
+	}
+
 	rawJSON, status, err := executeRequest(b.opts)
 	if err != nil {
 		return emptySignalResponse, status, err
@@ -92,13 +103,14 @@ func (b *signalBuilder) Execute() (*SignalResponse, StatusResponse, error) {
 }
 
 type signalOpts struct {
-	pubnub     *PubNub
-	Message    interface{}
-	Channel    string
-	UsePost    bool
-	QueryParam map[string]string
-	Transport  http.RoundTripper
-	ctx        Context
+	pubnub      *PubNub
+	Message     interface{}
+	Channel     string
+	UsePost     bool
+	QueryParam  map[string]string
+	RetryPolicy *RetryPolicy
+	Transport   http.RoundTripper
+	ctx         Context
 }
 
 func (o *signalOpts) config() Config {
@@ -135,7 +147,7 @@ func (o *signalOpts) buildPath() (string, error) {
 	}
 
 	var msg string
-	jsonEncBytes, errEnc := json.Marshal(o.Message)
+	jsonEncBytes, errEnc := o.pubnub.Config.serializer().Marshal(o.Message)
 	if errEnc != nil {
 		o.pubnub.Config.Log.Printf("ERROR: Publish error: %s\n", errEnc.Error())
 		return "", errEnc
@@ -164,7 +176,7 @@ func (o *signalOpts) jobQueue() chan *JobQItem {
 
 func (o *signalOpts) buildBody() ([]byte, error) {
 	if o.UsePost {
-		jsonEncBytes, errEnc := json.Marshal(o.Message)
+		jsonEncBytes, errEnc := o.pubnub.Config.serializer().Marshal(o.Message)
 		if errEnc != nil {
 			o.pubnub.Config.Log.Printf("ERROR: Signal error: %s\n", errEnc.Error())
 			return []byte{}, errEnc
@@ -209,6 +221,17 @@ func (o *signalOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *signalOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *signalOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // SignalResponse is the response to Signal request.
 type SignalResponse struct {
 	Timestamp int64
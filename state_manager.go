@@ -2,6 +2,8 @@ package pubnub
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -20,6 +22,8 @@ type StateManager struct {
 type SubscriptionItem struct {
 	name  string
 	state map[string]interface{}
+	// silent items are left out of the heartbeats and the leave requests.
+	silent bool
 }
 
 func newStateManager() *StateManager {
@@ -80,6 +84,7 @@ func (m *StateManager) adaptSubscribeOperation(
 			} else {
 				m.channels[ch] = newSubscriptionItem(ch)
 			}
+			m.channels[ch].silent = subscribeOperation.PresenceSilent
 
 			if subscribeOperation.PresenceEnabled {
 				m.presenceChannels[ch] = newSubscriptionItem(ch)
@@ -101,6 +106,7 @@ func (m *StateManager) adaptSubscribeOperation(
 			} else {
 				m.groups[cg] = newSubscriptionItem(cg)
 			}
+			m.groups[cg].silent = subscribeOperation.PresenceSilent
 
 			if subscribeOperation.PresenceEnabled {
 				m.presenceGroups[cg] = newSubscriptionItem(cg)
@@ -164,13 +170,13 @@ func (m *StateManager) createStatePayload() map[string]interface{} {
 	stateResponse := make(map[string]interface{})
 
 	for _, ch := range m.channels {
-		if len(ch.state) != 0 {
+		if len(ch.state) != 0 && !ch.silent {
 			stateResponse[ch.name] = ch.state
 		}
 	}
 
 	for _, gr := range m.groups {
-		if len(gr.state) != 0 {
+		if len(gr.state) != 0 && !gr.silent {
 			stateResponse[gr.name] = gr.state
 		}
 	}
@@ -178,6 +184,93 @@ func (m *StateManager) createStatePayload() map[string]interface{} {
 	return stateResponse
 }
 
+// prepareStateOperations groups the channels and groups with a state by their
+// state, the presence-silent ones are left out.
+func (m *StateManager) prepareStateOperations() []StateOperation {
+	m.RLock()
+	defer m.RUnlock()
+
+	var operations []StateOperation
+	operationFor := func(state map[string]interface{}) *StateOperation {
+		for i := range operations {
+			if reflect.DeepEqual(operations[i].state, state) {
+				return &operations[i]
+			}
+		}
+		operations = append(operations, StateOperation{state: state})
+		return &operations[len(operations)-1]
+	}
+
+	for _, name := range sortedItemNames(m.channels) {
+		if item := m.channels[name]; len(item.state) != 0 && !item.silent {
+			op := operationFor(item.state)
+			op.channels = append(op.channels, name)
+		}
+	}
+	for _, name := range sortedItemNames(m.groups) {
+		if item := m.groups[name]; len(item.state) != 0 && !item.silent {
+			op := operationFor(item.state)
+			op.channelGroups = append(op.channelGroups, name)
+		}
+	}
+	return operations
+}
+
+func sortedItemNames(storage map[string]*SubscriptionItem) []string {
+	names := make([]string, 0, len(storage))
+	for name := range storage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prepareHeartbeatLists returns the subscribed channels and groups which
+// aren't presence-silent.
+func (m *StateManager) prepareHeartbeatLists() ([]string, []string) {
+	m.RLock()
+	defer m.RUnlock()
+
+	channels := []string{}
+	for _, v := range m.channels {
+		if !v.silent {
+			channels = append(channels, v.name)
+		}
+	}
+	groups := []string{}
+	for _, v := range m.groups {
+		if !v.silent {
+			groups = append(groups, v.name)
+		}
+	}
+	return channels, groups
+}
+
+// hasHeartbeatItems reports whether a subscribed channel or group isn't
+// presence-silent.
+func (m *StateManager) hasHeartbeatItems() bool {
+	channels, groups := m.prepareHeartbeatLists()
+	return len(channels) > 0 || len(groups) > 0
+}
+
+// filterSilent drops the presence-silent channels and groups, the ones not
+// subscribed are kept.
+func (m *StateManager) filterSilent(channels, groups []string) ([]string, []string) {
+	m.RLock()
+	defer m.RUnlock()
+
+	filter := func(names []string, storage map[string]*SubscriptionItem) []string {
+		response := []string{}
+		for _, name := range names {
+			if item, ok := storage[name]; !ok || !item.silent {
+				response = append(response, name)
+			}
+		}
+		return response
+	}
+	return filter(channels, m.channels), filter(groups, m.groups)
+}
+
 func (m *StateManager) isEmpty() bool {
 	m.RLock()
 	defer m.RUnlock()
@@ -0,0 +1,74 @@
+package pubnub
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateManagerPresenceSilent(t *testing.T) {
+	assert := assert.New(t)
+	m := newStateManager()
+
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels:       []string{"data"},
+		ChannelGroups:  []string{"data-cg"},
+		State:          map[string]interface{}{"k": "v"},
+		PresenceSilent: true,
+	})
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"chat"},
+		State:    map[string]interface{}{"k": "v"},
+	})
+
+	channels, groups := m.prepareHeartbeatLists()
+	assert.Equal([]string{"chat"}, channels)
+	assert.Equal([]string{}, groups)
+	assert.True(m.hasHeartbeatItems())
+	assert.Equal(map[string]interface{}{"chat": map[string]interface{}{"k": "v"}}, m.createStatePayload())
+
+	all := m.prepareChannelList(false)
+	sort.Strings(all)
+	assert.Equal([]string{"chat", "data"}, all)
+
+	channels, groups = m.filterSilent([]string{"data", "chat", "unknown"}, []string{"data-cg"})
+	assert.Equal([]string{"chat", "unknown"}, channels)
+	assert.Equal([]string{}, groups)
+
+	m.adaptUnsubscribeOperation(&UnsubscribeOperation{Channels: []string{"chat"}})
+	assert.False(m.hasHeartbeatItems())
+
+	// subscribing again without the flag announces presence
+	m.adaptSubscribeOperation(&SubscribeOperation{Channels: []string{"data"}})
+	channels, _ = m.prepareHeartbeatLists()
+	assert.Equal([]string{"data"}, channels)
+}
+
+func TestStateManagerPrepareStateOperations(t *testing.T) {
+	assert := assert.New(t)
+	m := newStateManager()
+
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels:      []string{"b", "a"},
+		ChannelGroups: []string{"cg"},
+		State:         map[string]interface{}{"k": 1},
+	})
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"c"},
+		State:    map[string]interface{}{"k": 2},
+	})
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels:       []string{"silent"},
+		State:          map[string]interface{}{"k": 1},
+		PresenceSilent: true,
+	})
+	m.adaptSubscribeOperation(&SubscribeOperation{
+		Channels: []string{"stateless"},
+	})
+
+	assert.Equal([]StateOperation{
+		{channels: []string{"a", "b"}, channelGroups: []string{"cg"}, state: map[string]interface{}{"k": 1}},
+		{channels: []string{"c"}, state: map[string]interface{}{"k": 2}},
+	}, m.prepareStateOperations())
+}
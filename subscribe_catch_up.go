@@ -0,0 +1,158 @@
+package pubnub
+
+import (
+	"sort"
+	"strconv"
+)
+
+// maxCatchUpPages limits the number of Fetch requests made per channel by a
+// single catch-up.
+const maxCatchUpPages = 10
+
+// caughtUpKey identifies a message announced by the catch-up.
+func caughtUpKey(channel string, timetoken int64) string {
+	return strconv.FormatInt(timetoken, 10) + "/" + channel
+}
+
+// trackDelivery records the timetoken of a message about to be announced on
+// channel. It returns false if the message was already announced by the
+// catch-up and has to be skipped.
+func (m *SubscriptionManager) trackDelivery(channel string, timetoken int64) bool {
+	if !m.pubnub.Config.CatchUpOnReconnect || timetoken == 0 {
+		return true
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	key := caughtUpKey(channel, timetoken)
+	if _, ok := m.caughtUp[key]; ok {
+		delete(m.caughtUp, key)
+		return false
+	}
+	if timetoken > m.lastTimetokens[channel] {
+		m.lastTimetokens[channel] = timetoken
+	}
+	return true
+}
+
+// catchUp fetches the messages published on the subscribed channels since the
+// subscribe cursor, or the last announced message of the channel if newer,
+// and announces them in order, marked as CatchUp. It runs before the
+// subscribe loop is restarted after a reconnection, the messages replayed by
+// subscribe afterwards are skipped by trackDelivery.
+func (m *SubscriptionManager) catchUp() {
+	channels := m.stateManager.prepareChannelList(false)
+	sort.Strings(channels)
+
+	m.Lock()
+	cursor := m.timetoken
+	if cursor == 0 && m.storedTimetoken > 0 {
+		cursor = m.storedTimetoken
+	}
+	// the replays of a previous catch-up not seen by now won't come
+	m.caughtUp = make(map[string]struct{})
+	m.Unlock()
+
+	if len(channels) == 0 {
+		return
+	}
+
+	// messages published from now on are delivered by subscribe
+	res, status, err := m.pubnub.Time().Execute()
+	if err != nil {
+		m.announceCatchUpError(channels, status, err)
+		return
+	}
+
+	for _, ch := range channels {
+		m.catchUpChannel(ch, cursor, res.Timetoken)
+	}
+}
+
+// catchUpChannel announces the messages of channel published after cursor and
+// before now.
+func (m *SubscriptionManager) catchUpChannel(channel string, cursor, now int64) {
+	m.RLock()
+	if last := m.lastTimetokens[channel]; last > cursor {
+		cursor = last
+	}
+	m.RUnlock()
+	if cursor == 0 {
+		return
+	}
+
+	// Fetch returns the newest messages of the range, the older ones are
+	// paged with Start, which is exclusive. End is inclusive.
+	var items []FetchResponseItem
+	start := now
+	for page := 0; ; page++ {
+		if page == maxCatchUpPages {
+			m.pubnub.Config.Log.Println("catch-up: page limit reached for", channel)
+			break
+		}
+		res, status, err := m.pubnub.Fetch().Channels([]string{channel}).
+			Start(start).End(cursor + 1).Count(maxCountFetch).IncludeMeta(true).Execute()
+		if err != nil {
+			m.announceCatchUpError([]string{channel}, status, err)
+			return
+		}
+
+		batch := res.Messages[channel]
+		items = append(batch, items...)
+		if len(batch) < maxCountFetch {
+			break
+		}
+		oldest, err := strconv.ParseInt(batch[0].Timetoken, 10, 64)
+		if err != nil || oldest <= cursor+1 {
+			break
+		}
+		start = oldest
+	}
+
+	for _, item := range items {
+		timetoken, err := strconv.ParseInt(item.Timetoken, 10, 64)
+		if err != nil {
+			m.pubnub.Config.Log.Println("catch-up: invalid timetoken", item.Timetoken)
+			continue
+		}
+		if item.MessageType != 0 {
+			// only regular messages are caught up
+			continue
+		}
+		if m.isDuplicate(channel, item.Timetoken, item.UUID) {
+			m.pubnub.Config.Log.Println("catch-up: skipping duplicate message,", channel, item.Timetoken)
+			continue
+		}
+
+		m.Lock()
+		if timetoken > m.lastTimetokens[channel] {
+			m.lastTimetokens[channel] = timetoken
+		}
+		m.caughtUp[caughtUpKey(channel, timetoken)] = struct{}{}
+		m.Unlock()
+
+		m.listenerManager.announceMessage(&PNMessage{
+			Message:           item.Message,
+			UserMetadata:      item.Meta,
+			SubscribedChannel: channel,
+			Channel:           channel,
+			Publisher:         item.UUID,
+			Timetoken:         timetoken,
+			CatchUp:           true,
+		})
+	}
+}
+
+func (m *SubscriptionManager) announceCatchUpError(channels []string, status StatusResponse, err error) {
+	pnStatus := &PNStatus{
+		Category:         categoryForError(err),
+		ErrorData:        err,
+		Error:            true,
+		Operation:        PNFetchMessagesOperation,
+		StatusCode:       status.StatusCode,
+		AffectedChannels: channels,
+	}
+	m.pubnub.Config.Log.Println("catch-up: err", err, pnStatus)
+	m.listenerManager.announceStatus(pnStatus)
+}
@@ -0,0 +1,157 @@
+package pubnub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatchUpAnnouncesMissedMessages(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/time/0",
+		Query:              "",
+		ResponseBody:       `[300]`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v3/history/sub-key/demo/channel/ch",
+		Query:              "start=300&end=121&max=100&reverse=false&include_meta=true&include_message_type=true&include_uuid=true",
+		ResponseBody:       `{"status": 200, "error": false, "error_message": "", "channels": {"ch":[{"message": "first", "timetoken": "150", "uuid": "publisher", "message_type": null}, {"message": "second", "timetoken": "200", "uuid": "publisher", "message_type": null}]}}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature", "l_time", "l_hist"},
+		ResponseStatusCode: 200,
+	})
+	// no message was announced on quiet, caught up from the subscribe cursor
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v3/history/sub-key/demo/channel/quiet",
+		Query:              "start=300&end=101&max=100&reverse=false&include_meta=true&include_message_type=true&include_uuid=true",
+		ResponseBody:       `{"status": 200, "error": false, "error_message": "", "channels": {"quiet":[{"message": "missed", "timetoken": "110", "uuid": "publisher", "message_type": null}]}}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature", "l_time", "l_hist"},
+		ResponseStatusCode: 200,
+	})
+
+	config := NewDemoConfig()
+	config.CatchUpOnReconnect = true
+	pn := NewPubNub(config)
+	pn.SetClient(interceptor.GetClient())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	m := pn.subscriptionManager
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{Channels: []string{"quiet", "ch"}})
+	m.Lock()
+	m.timetoken = 100
+	m.Unlock()
+	assert.True(m.trackDelivery("ch", 120))
+
+	done := make(chan bool)
+	go func() {
+		m.catchUp()
+		close(done)
+	}()
+
+	for _, expected := range []string{"first", "second", "missed"} {
+		select {
+		case msg := <-listener.Message:
+			assert.Equal(expected, msg.Message)
+			assert.Equal("publisher", msg.Publisher)
+			assert.True(msg.CatchUp)
+		case <-time.After(2 * time.Second):
+			assert.Fail("caught up message not delivered")
+			return
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		assert.Fail("catch-up didn't finish")
+	}
+
+	// replayed by subscribe after the catch-up
+	processSubscribePayload(m, subscribeMessage{
+		Channel:         "ch",
+		Payload:         "second",
+		PublishMetaData: publishMetadata{PublishTimetoken: "200"},
+	})
+	processSubscribePayload(m, subscribeMessage{
+		Channel:         "ch",
+		Payload:         "live",
+		PublishMetaData: publishMetadata{PublishTimetoken: "250"},
+	})
+
+	select {
+	case msg := <-listener.Message:
+		assert.Equal("live", msg.Message)
+		assert.False(msg.CatchUp)
+	case <-time.After(2 * time.Second):
+		assert.Fail("live message not delivered")
+	}
+
+	// replays of older messages not caught up aren't skipped
+	assert.True(m.trackDelivery("ch", 130))
+	assert.True(m.trackDelivery("ch", 130))
+}
+
+func TestCatchUpSkipsDuplicates(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/time/0",
+		Query:              "",
+		ResponseBody:       `[300]`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v3/history/sub-key/demo/channel/ch",
+		Query:              "start=300&end=101&max=100&reverse=false&include_meta=true&include_message_type=true&include_uuid=true",
+		ResponseBody:       `{"status": 200, "error": false, "error_message": "", "channels": {"ch":[{"message": "seen", "timetoken": "150", "uuid": "publisher", "message_type": null}, {"message": "missed", "timetoken": "200", "uuid": "publisher", "message_type": null}]}}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature", "l_time", "l_hist"},
+		ResponseStatusCode: 200,
+	})
+
+	config := NewDemoConfig()
+	config.CatchUpOnReconnect = true
+	config.DedupeCacheSize = 10
+	pn := NewPubNub(config)
+	pn.SetClient(interceptor.GetClient())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	m := pn.subscriptionManager
+	m.stateManager.adaptSubscribeOperation(&SubscribeOperation{Channels: []string{"ch"}})
+	m.Lock()
+	m.timetoken = 100
+	m.Unlock()
+	// already announced, e.g. through a channel group
+	assert.False(m.isDuplicate("ch", "150", "publisher"))
+
+	go m.catchUp()
+
+	select {
+	case msg := <-listener.Message:
+		assert.Equal("missed", msg.Message)
+		assert.True(msg.CatchUp)
+	case <-time.After(2 * time.Second):
+		assert.Fail("caught up message not delivered")
+	}
+	assert.Equal(int64(1), m.SuppressedDuplicates())
+}
+
+func TestCatchUpDisabled(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	assert.True(pn.subscriptionManager.trackDelivery("ch", 100))
+	assert.True(pn.subscriptionManager.trackDelivery("ch", 100))
+	assert.Equal(0, len(pn.subscriptionManager.lastTimetokens))
+}
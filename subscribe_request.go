@@ -2,7 +2,6 @@ package pubnub
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -83,6 +82,17 @@ func (b *subscribeBuilder) WithPresence(pres bool) *subscribeBuilder {
 	return b
 }
 
+// PresenceSilent as true subscribes to the channels and channel groups without
+// announcing presence on them: they are left out of the heartbeats and no
+// leave is sent when unsubscribing. The heartbeat parameter of subscribe
+// applies to the whole request, it's only omitted when every subscribed
+// channel and channel group is presence-silent.
+func (b *subscribeBuilder) PresenceSilent(silent bool) *subscribeBuilder {
+	b.operation.PresenceSilent = silent
+
+	return b
+}
+
 // State sets the state of the channels while subscribing.
 func (b *subscribeBuilder) State(state map[string]interface{}) *subscribeBuilder {
 	b.operation.State = state
@@ -96,9 +106,14 @@ func (b *subscribeBuilder) QueryParam(queryParam map[string]string) *subscribeBu
 	return b
 }
 
-// Execute runs the Subscribe operation.
-func (b *subscribeBuilder) Execute() {
+// Execute runs the Subscribe operation. The returned Subscription scopes
+// listeners to the subscribed channels and channel groups, and unsubscribes
+// from them without affecting the other subscriptions.
+func (b *subscribeBuilder) Execute() *Subscription {
+	subscription := newSubscription(b.opts.pubnub, b.operation)
 	b.opts.pubnub.subscriptionManager.adaptSubscribe(b.operation)
+
+	return subscription
 }
 
 func (o *subscribeOpts) config() Config {
@@ -124,7 +139,7 @@ func (o *subscribeOpts) validate() error {
 	}
 
 	if o.State != nil {
-		state, err := json.Marshal(o.State)
+		state, err := o.pubnub.Config.serializer().Marshal(o.State)
 		if err != nil {
 			return newValidationError(o, err.Error())
 		}
@@ -216,3 +231,13 @@ func (o *subscribeOpts) telemetryManager() *TelemetryManager {
 func (o *subscribeOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
+
+func (o *subscribeOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+// retryPolicy disables the retries, the subscribe requests are repeated by their
+// managers.
+func (o *subscribeOpts) retryPolicy() RetryPolicy {
+	return RetryPolicy{}
+}
@@ -3,8 +3,10 @@ package pubnub
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	h "github.com/pubnub/go/v7/tests/helpers"
+	"github.com/pubnub/go/v7/tests/stubs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -194,3 +196,54 @@ func TestSubscribeValidateState(t *testing.T) {
 
 	assert.Nil(opts.validate())
 }
+
+func TestSubscribePresenceSilent(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+	// heartbeat isn't ignored, the stubs match only without it
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/subscribe/demo/data/0",
+		Query:              "",
+		ResponseBody:       `{"t":{"t":"15000000000000000","r":12},"m":[]}`,
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+	})
+	interceptor.AddStub(&stubs.Stub{
+		Method:             "GET",
+		Path:               "/v2/subscribe/demo/data/0",
+		Query:              "tt=15000000000000000&tr=12",
+		IgnoreQueryKeys:    []string{"uuid", "pnsdk", "timestamp", "signature"},
+		ResponseStatusCode: 200,
+		Hang:               true,
+	})
+
+	pn := NewPubNub(NewDemoConfig())
+	pn.SetSubscribeClient(interceptor.GetClient())
+	// a leave request would fail with No Stub Matched
+	pn.SetClient(interceptor.GetClient())
+	defer pn.Destroy()
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	subscription := pn.Subscribe().Channels([]string{"data"}).PresenceSilent(true).Execute()
+
+	waitStatus := func(category StatusCategory) {
+		for {
+			select {
+			case status := <-listener.Status:
+				if status.Category == category {
+					return
+				}
+				assert.False(status.Error, "%v", status)
+			case <-time.After(5 * time.Second):
+				assert.Fail("status not announced", "%v", category)
+				return
+			}
+		}
+	}
+	waitStatus(PNConnectedCategory)
+
+	subscription.Unsubscribe()
+	waitStatus(PNAcknowledgmentCategory)
+}
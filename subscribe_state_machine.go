@@ -0,0 +1,154 @@
+package pubnub
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SubscribeStateTransition describes a single change of the subscribe loop state,
+// along with the reason and the error (if any) which triggered it.
+type SubscribeStateTransition struct {
+	From      SubscribeState
+	To        SubscribeState
+	Reason    string
+	Error     error
+	Timestamp time.Time
+}
+
+// subscribeStateTransitions lists the allowed target states for every state.
+var subscribeStateTransitions = map[SubscribeState][]SubscribeState{
+	PNSubscribeStateStopped: {
+		PNSubscribeStateHandshaking,
+		PNSubscribeStateFailed,
+	},
+	PNSubscribeStateHandshaking: {
+		PNSubscribeStateReceiving,
+		PNSubscribeStateReconnecting,
+		PNSubscribeStateFailed,
+		PNSubscribeStateStopped,
+	},
+	PNSubscribeStateReceiving: {
+		PNSubscribeStateHandshaking,
+		PNSubscribeStateReconnecting,
+		PNSubscribeStateFailed,
+		PNSubscribeStateStopped,
+	},
+	PNSubscribeStateReconnecting: {
+		PNSubscribeStateHandshaking,
+		PNSubscribeStateReceiving,
+		PNSubscribeStateFailed,
+		PNSubscribeStateStopped,
+	},
+	PNSubscribeStateFailed: {
+		PNSubscribeStateHandshaking,
+	},
+}
+
+// subscribeStateMachine keeps track of the state of the subscribe loop.
+// Every started loop gets a new generation, transitions requested by a loop
+// which was already replaced by a newer one are ignored.
+type subscribeStateMachine struct {
+	sync.RWMutex
+
+	state      SubscribeState
+	last       SubscribeStateTransition
+	generation int64
+	handlers   []func(SubscribeStateTransition)
+	pubnub     *PubNub
+}
+
+func newSubscribeStateMachine(pubnub *PubNub) *subscribeStateMachine {
+	return &subscribeStateMachine{
+		state:  PNSubscribeStateStopped,
+		pubnub: pubnub,
+		last: SubscribeStateTransition{
+			From:      PNSubscribeStateStopped,
+			To:        PNSubscribeStateStopped,
+			Reason:    "initialized",
+			Timestamp: time.Now(),
+		},
+	}
+}
+
+func canTransition(from, to SubscribeState) bool {
+	for _, s := range subscribeStateTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// startLoop registers a new subscribe loop, moves the machine to the
+// handshaking state and returns the generation of the loop.
+func (m *subscribeStateMachine) startLoop(reason string) int64 {
+	m.Lock()
+	m.generation++
+	generation := m.generation
+	m.Unlock()
+
+	m.transition(generation, PNSubscribeStateHandshaking, reason, nil)
+
+	return generation
+}
+
+// transition moves the machine to the state `to`. A generation of 0 is not
+// bound to a particular loop. It returns false if the transition was
+// rejected, either because the loop is stale or the transition isn't allowed.
+func (m *subscribeStateMachine) transition(generation int64, to SubscribeState, reason string, err error) bool {
+	m.Lock()
+	if generation != 0 && generation != m.generation {
+		m.Unlock()
+		m.pubnub.Config.Log.Println(fmt.Sprintf("subscribe state: ignoring %s from stale loop %d: %s", to, generation, reason))
+		return false
+	}
+	from := m.state
+	if from == to || !canTransition(from, to) {
+		m.Unlock()
+		if from != to {
+			m.pubnub.Config.Log.Println(fmt.Sprintf("subscribe state: transition %s -> %s not allowed: %s", from, to, reason))
+		}
+		return false
+	}
+
+	t := SubscribeStateTransition{
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		Error:     err,
+		Timestamp: time.Now(),
+	}
+	m.state = to
+	m.last = t
+	handlers := make([]func(SubscribeStateTransition), len(m.handlers))
+	copy(handlers, m.handlers)
+	m.Unlock()
+
+	m.pubnub.Config.Log.Println(fmt.Sprintf("subscribe state: %s -> %s: %s %v", from, to, reason, err))
+
+	for _, h := range handlers {
+		h(t)
+	}
+	return true
+}
+
+func (m *subscribeStateMachine) currentState() SubscribeState {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.state
+}
+
+func (m *subscribeStateMachine) lastTransition() SubscribeStateTransition {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.last
+}
+
+func (m *subscribeStateMachine) addHandler(handler func(SubscribeStateTransition)) {
+	m.Lock()
+	m.handlers = append(m.handlers, handler)
+	m.Unlock()
+}
@@ -0,0 +1,125 @@
+package pubnub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/pnerr"
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeStateMachineTransitions(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	sm := newSubscribeStateMachine(pn)
+
+	var transitions []SubscribeStateTransition
+	sm.addHandler(func(t SubscribeStateTransition) {
+		transitions = append(transitions, t)
+	})
+
+	assert.Equal(PNSubscribeStateStopped, sm.currentState())
+
+	gen := sm.startLoop("started")
+	assert.Equal(PNSubscribeStateHandshaking, sm.currentState())
+
+	err := errors.New("timeout")
+	assert.True(sm.transition(gen, PNSubscribeStateReconnecting, "timed out", err))
+	assert.True(sm.transition(gen, PNSubscribeStateReceiving, "received", nil))
+
+	last := sm.lastTransition()
+	assert.Equal(PNSubscribeStateReconnecting, last.From)
+	assert.Equal(PNSubscribeStateReceiving, last.To)
+	assert.Equal("received", last.Reason)
+
+	assert.Equal(3, len(transitions))
+	assert.Equal(err, transitions[1].Error)
+	assert.Equal("timed out", transitions[1].Reason)
+}
+
+func TestSubscribeStateMachineIgnoresStaleLoop(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	sm := newSubscribeStateMachine(pn)
+
+	oldGen := sm.startLoop("first")
+	newGen := sm.startLoop("second")
+	assert.True(sm.transition(newGen, PNSubscribeStateReceiving, "received", nil))
+
+	assert.False(sm.transition(oldGen, PNSubscribeStateStopped, "cancelled", nil))
+	assert.Equal(PNSubscribeStateReceiving, sm.currentState())
+}
+
+func TestSubscribeStateMachineFailedIsSticky(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	sm := newSubscribeStateMachine(pn)
+
+	gen := sm.startLoop("started")
+	assert.True(sm.transition(gen, PNSubscribeStateFailed, "access denied", nil))
+	assert.False(sm.transition(0, PNSubscribeStateStopped, "unsubscribed", nil))
+	assert.Equal(PNSubscribeStateFailed, sm.currentState())
+
+	sm.startLoop("subscribed again")
+	assert.Equal(PNSubscribeStateHandshaking, sm.currentState())
+}
+
+func TestClassifySubscribeErrorReconnectionPolicy(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	connErr := pnerr.NewConnectionError("Failed to execute request", errors.New("dial tcp: connection refused"))
+
+	action := pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.Equal(PNUnknownCategory, action.category)
+	assert.Equal(PNSubscribeStateFailed, action.state)
+
+	pn.Config.PNReconnectionPolicy = PNLinearPolicy
+	action = pn.subscriptionManager.classifySubscribeError(connErr)
+	assert.Equal(PNSubscribeStateReconnecting, action.state)
+	assert.False(action.retry)
+}
+
+func TestClassifySubscribeErrorServerError(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	action := pn.subscriptionManager.classifySubscribeError(&pnerr.ServerError{StatusCode: 503})
+	assert.Equal(PNServerErrorCategory, action.category)
+	assert.Equal(PNSubscribeStateReconnecting, action.state)
+	assert.True(action.retry)
+
+	action = pn.subscriptionManager.classifySubscribeError(&pnerr.ServerError{StatusCode: 403})
+	assert.Equal(PNAccessDeniedCategory, action.category)
+	assert.Equal(PNSubscribeStateFailed, action.state)
+	assert.True(action.unsubscribe)
+}
+
+func TestSubscribeStateFailedOnServerError(t *testing.T) {
+	assert := assert.New(t)
+	interceptor := stubs.NewInterceptor()
+
+	pn := NewPubNub(NewDemoConfig())
+	pn.SetSubscribeClient(interceptor.GetClient())
+	pn.SetClient(interceptor.GetClient())
+
+	failed := make(chan SubscribeStateTransition, 1)
+	pn.AddSubscribeStateHandler(func(t SubscribeStateTransition) {
+		if t.To == PNSubscribeStateFailed {
+			failed <- t
+		}
+	})
+
+	pn.Subscribe().Channels([]string{"ch"}).Execute()
+
+	select {
+	case tr := <-failed:
+		assert.Equal(PNSubscribeStateHandshaking, tr.From)
+		assert.NotNil(tr.Error)
+	case <-time.After(5 * time.Second):
+		assert.Fail("subscribe loop didn't fail")
+	}
+	assert.Equal(PNSubscribeStateFailed, pn.GetSubscribeState())
+}
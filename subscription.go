@@ -0,0 +1,212 @@
+package pubnub
+
+import (
+	"strings"
+	"sync"
+)
+
+// Subscription is the handle of the channels and channel groups subscribed by
+// a single Subscribe call.
+// The listeners added to a Subscription only receive the events of its
+// channels and channel groups, and the status events which either affect them
+// or aren't bound to a channel. Unsubscribe leaves only the channels and
+// channel groups no other Subscription holds.
+type Subscription struct {
+	sync.Mutex
+
+	pubnub        *PubNub
+	channels      []string
+	channelGroups []string
+	withPresence  bool
+	unsubscribed  bool
+}
+
+func newSubscription(pubnub *PubNub, operation *SubscribeOperation) *Subscription {
+	return &Subscription{
+		pubnub:        pubnub,
+		channels:      append([]string(nil), operation.Channels...),
+		channelGroups: append([]string(nil), operation.ChannelGroups...),
+		withPresence:  operation.PresenceEnabled,
+	}
+}
+
+// Channels returns the channels of the subscription.
+func (s *Subscription) Channels() []string {
+	return append([]string(nil), s.channels...)
+}
+
+// ChannelGroups returns the channel groups of the subscription.
+func (s *Subscription) ChannelGroups() []string {
+	return append([]string(nil), s.channelGroups...)
+}
+
+// AddListener adds a listener which only receives the events of the subscription.
+func (s *Subscription) AddListener(listener *Listener) {
+	s.pubnub.subscriptionManager.listenerManager.addScopedListener(listener, s)
+}
+
+// RemoveListener removes a listener of the subscription.
+func (s *Subscription) RemoveListener(listener *Listener) {
+	s.pubnub.subscriptionManager.listenerManager.removeListener(listener)
+}
+
+// AddEventListener adds a callback based listener which only receives the events of the subscription.
+func (s *Subscription) AddEventListener(listener *EventListener) {
+	s.pubnub.subscriptionManager.listenerManager.addScopedEventListener(listener, s)
+}
+
+// RemoveEventListener removes a callback based listener of the subscription.
+func (s *Subscription) RemoveEventListener(listener *EventListener) {
+	s.pubnub.subscriptionManager.listenerManager.removeEventListener(listener)
+}
+
+// Unsubscribe releases the channels and channel groups of the subscription.
+// The ones still held by other subscriptions stay subscribed. Calling
+// Unsubscribe more than once has no effect.
+func (s *Subscription) Unsubscribe() {
+	s.Lock()
+	unsubscribed := s.unsubscribed
+	s.unsubscribed = true
+	s.Unlock()
+
+	if !unsubscribed {
+		s.pubnub.subscriptionManager.releaseSubscription(s)
+	}
+}
+
+func (s *Subscription) matchesEvent(channel, subscription string, presence bool) bool {
+	name := channel
+	if subscription != "" {
+		name = subscription
+	}
+
+	if presence {
+		if hasString(s.channels, name+"-pnpres") || hasString(s.channelGroups, name+"-pnpres") {
+			return true
+		}
+		if !s.withPresence {
+			return false
+		}
+	}
+
+	return hasString(s.channels, name) || hasString(s.channelGroups, name)
+}
+
+func (s *Subscription) matchesStatus(status *PNStatus) bool {
+	if len(status.AffectedChannels) == 0 && len(status.AffectedChannelGroups) == 0 {
+		return true
+	}
+
+	for _, ch := range status.AffectedChannels {
+		if hasString(s.channels, ch) || (s.withPresence && hasString(s.channels, trimPresenceSuffix(ch))) {
+			return true
+		}
+	}
+	for _, cg := range status.AffectedChannelGroups {
+		if hasString(s.channelGroups, cg) || (s.withPresence && hasString(s.channelGroups, trimPresenceSuffix(cg))) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriptionSet groups several subscriptions. The listeners added to a
+// SubscriptionSet receive the events of all of its subscriptions.
+type SubscriptionSet struct {
+	sync.RWMutex
+
+	pubnub        *PubNub
+	subscriptions []*Subscription
+}
+
+// Add adds a subscription to the set.
+func (s *SubscriptionSet) Add(subscription *Subscription) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, sub := range s.subscriptions {
+		if sub == subscription {
+			return
+		}
+	}
+	s.subscriptions = append(s.subscriptions, subscription)
+}
+
+// Remove removes a subscription from the set, the subscription stays subscribed.
+func (s *SubscriptionSet) Remove(subscription *Subscription) {
+	s.Lock()
+	defer s.Unlock()
+
+	for i, sub := range s.subscriptions {
+		if sub == subscription {
+			s.subscriptions = append(s.subscriptions[:i], s.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Subscriptions returns the subscriptions of the set.
+func (s *SubscriptionSet) Subscriptions() []*Subscription {
+	s.RLock()
+	defer s.RUnlock()
+
+	return append([]*Subscription(nil), s.subscriptions...)
+}
+
+// AddListener adds a listener which only receives the events of the subscriptions of the set.
+func (s *SubscriptionSet) AddListener(listener *Listener) {
+	s.pubnub.subscriptionManager.listenerManager.addScopedListener(listener, s)
+}
+
+// RemoveListener removes a listener of the set.
+func (s *SubscriptionSet) RemoveListener(listener *Listener) {
+	s.pubnub.subscriptionManager.listenerManager.removeListener(listener)
+}
+
+// AddEventListener adds a callback based listener which only receives the events of the subscriptions of the set.
+func (s *SubscriptionSet) AddEventListener(listener *EventListener) {
+	s.pubnub.subscriptionManager.listenerManager.addScopedEventListener(listener, s)
+}
+
+// RemoveEventListener removes a callback based listener of the set.
+func (s *SubscriptionSet) RemoveEventListener(listener *EventListener) {
+	s.pubnub.subscriptionManager.listenerManager.removeEventListener(listener)
+}
+
+// Unsubscribe unsubscribes all the subscriptions of the set.
+func (s *SubscriptionSet) Unsubscribe() {
+	for _, sub := range s.Subscriptions() {
+		sub.Unsubscribe()
+	}
+}
+
+func (s *SubscriptionSet) matchesEvent(channel, subscription string, presence bool) bool {
+	for _, sub := range s.Subscriptions() {
+		if sub.matchesEvent(channel, subscription, presence) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SubscriptionSet) matchesStatus(status *PNStatus) bool {
+	for _, sub := range s.Subscriptions() {
+		if sub.matchesStatus(status) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func trimPresenceSuffix(name string) string {
+	return strings.TrimSuffix(name, "-pnpres")
+}
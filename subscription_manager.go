@@ -3,6 +3,7 @@ package pubnub
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -10,7 +11,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/pubnub/go/v7/utils"
+	"github.com/pubnub/go/v7/pnerr"
 )
 
 // SubscriptionManager Events:
@@ -25,8 +26,10 @@ import (
 // - PNUnsubscribeOperation - after leave request was fulfilled and server is
 // notified about unsubscibed items
 // Announcement:
-// Status, Message and Presence announcement happens in a distinct goroutine.
-// It doesn't block subscribe loop.
+// Status, Message and Presence events are queued per listener and delivered
+// in order by a distinct goroutine of each listener. It doesn't block
+// subscribe loop unless the queue is full and the ListenerOverflowPolicy is
+// PNListenerOverflowBlock.
 // Keep in mind that each listener will receive the same pointer to a response
 // object. You may wish to create a shallow copy of either the response or the
 // response message by you own to not affect the other listeners.
@@ -43,6 +46,7 @@ type SubscriptionManager struct {
 	stateManager        *StateManager
 	pubnub              *PubNub
 	reconnectionManager *ReconnectionManager
+	stateMachine        *subscribeStateMachine
 	transport           http.RoundTripper
 	messages            chan subscribeMessage
 	ctx                 Context
@@ -56,6 +60,10 @@ type SubscriptionManager struct {
 	// When changing the channel mix, store the timetoken for a later date
 	storedTimetoken int64
 
+	// Region of the cursor loaded from Config.CursorStore, used along with
+	// storedTimetoken.
+	storedRegion int8
+
 	region int8
 
 	subscriptionStateAnnounced   bool
@@ -65,6 +73,24 @@ type SubscriptionManager struct {
 	queryParam                   map[string]string
 	channelsOpen                 bool
 	requestSentAt                int64
+
+	// Number of Subscriptions holding each channel and channel group, the
+	// presence channels and groups are counted with the -pnpres suffix.
+	channelRefs map[string]int
+	groupRefs   map[string]int
+
+	// Timetoken of the last message announced on each channel, used by the
+	// catch-up after a reconnection.
+	lastTimetokens map[string]int64
+	// Channel and timetoken of the messages announced by the catch-up, their
+	// replay by subscribe is skipped once.
+	caughtUp map[string]struct{}
+
+	dedupe    *dedupeCache
+	sequences *sequenceTracker
+
+	// UUID of the previous subscribe request, see checkStateUUID.
+	stateUUID string
 }
 
 // SubscribeOperation is the type to store the subscribe op params
@@ -72,6 +98,7 @@ type SubscribeOperation struct {
 	Channels         []string
 	ChannelGroups    []string
 	PresenceEnabled  bool
+	PresenceSilent   bool
 	Timetoken        int64
 	FilterExpression string
 	State            map[string]interface{}
@@ -107,13 +134,26 @@ func newSubscriptionManager(pubnub *PubNub, ctx Context) *SubscriptionManager {
 	manager.ctx, manager.subscribeCancel = contextWithCancel(backgroundContext)
 	manager.messages = make(chan subscribeMessage, 1000)
 	manager.reconnectionManager = newReconnectionManager(pubnub)
+	manager.stateMachine = newSubscribeStateMachine(pubnub)
 	manager.channelsOpen = true
+	manager.channelRefs = make(map[string]int)
+	manager.groupRefs = make(map[string]int)
+	manager.lastTimetokens = make(map[string]int64)
+	manager.caughtUp = make(map[string]struct{})
+	manager.dedupe = newDedupeCache()
+	manager.sequences = newSequenceTracker()
 	manager.Unlock()
 
 	if manager.pubnub.Config.PNReconnectionPolicy != PNNonePolicy {
 
 		manager.reconnectionManager.HandleReconnection(func() {
-			go manager.reconnect()
+			go func() {
+				if pubnub.Config.CatchUpOnReconnect {
+					manager.catchUp()
+				}
+				manager.reconnect()
+				manager.restoreState("reconnected")
+			}()
 
 			manager.Lock()
 			manager.subscriptionStateAnnounced = true
@@ -135,6 +175,7 @@ func newSubscriptionManager(pubnub *PubNub, ctx Context) *SubscriptionManager {
 	}
 
 	manager.reconnectionManager.HandleOnMaxReconnectionExhaustion(func() {
+		manager.stateMachine.transition(0, PNSubscribeStateFailed, "reconnection attempts exhausted", nil)
 		combinedChannels := manager.stateManager.prepareChannelList(true)
 		combinedGroups := manager.stateManager.prepareGroupList(true)
 
@@ -203,11 +244,17 @@ func (m *SubscriptionManager) adaptSubscribe(
 
 	m.Lock()
 
+	retainRefs(m.channelRefs, subscribeOperation.Channels, subscribeOperation.PresenceEnabled)
+	retainRefs(m.groupRefs, subscribeOperation.ChannelGroups, subscribeOperation.PresenceEnabled)
 	m.subscriptionStateAnnounced = false
 	m.queryParam = subscribeOperation.QueryParam
 
 	if subscribeOperation.Timetoken != 0 {
 		m.timetoken = subscribeOperation.Timetoken
+		// the replay was asked for, announce the caught up messages again
+		m.caughtUp = make(map[string]struct{})
+	} else if m.timetoken == 0 && m.storedTimetoken == -1 {
+		m.resumeFromCursorLocked()
 	}
 
 	if m.timetoken != 0 {
@@ -224,22 +271,30 @@ func (m *SubscriptionManager) adaptSubscribe(
 func (m *SubscriptionManager) adaptUnsubscribe(
 	unsubscribeOperation *UnsubscribeOperation) {
 	m.pubnub.Config.Log.Println("before adaptUnsubscribeOperation")
+	leaveChannels, leaveGroups := m.stateManager.filterSilent(unsubscribeOperation.Channels, unsubscribeOperation.ChannelGroups)
 	m.stateManager.adaptUnsubscribeOperation(unsubscribeOperation)
 	m.pubnub.Config.Log.Println("after adaptUnsubscribeOperation")
 
 	m.Lock()
+	for _, ch := range unsubscribeOperation.Channels {
+		delete(m.lastTimetokens, ch)
+	}
+	clearRefs(m.channelRefs, unsubscribeOperation.Channels)
+	clearRefs(m.groupRefs, unsubscribeOperation.ChannelGroups)
 	m.subscriptionStateAnnounced = false
 	m.Unlock()
 
 	go func() {
 		announceAck := false
-		if !m.pubnub.Config.SuppressLeaveEvents {
-			_, err := m.pubnub.Leave().Channels(unsubscribeOperation.Channels).
-				ChannelGroups(unsubscribeOperation.ChannelGroups).QueryParam(unsubscribeOperation.QueryParam).Execute()
+		allSilent := len(leaveChannels) == 0 && len(leaveGroups) == 0 &&
+			(len(unsubscribeOperation.Channels) > 0 || len(unsubscribeOperation.ChannelGroups) > 0)
+		if !m.pubnub.Config.SuppressLeaveEvents && !allSilent {
+			_, err := m.pubnub.Leave().Channels(leaveChannels).
+				ChannelGroups(leaveGroups).QueryParam(unsubscribeOperation.QueryParam).Execute()
 
 			if err != nil {
 				pnStatus := &PNStatus{
-					Category:              PNBadRequestCategory,
+					Category:              categoryForError(err),
 					ErrorData:             err,
 					Error:                 true,
 					Operation:             PNUnsubscribeOperation,
@@ -286,8 +341,101 @@ func (m *SubscriptionManager) adaptUnsubscribe(
 	m.pubnub.Config.Log.Println("after reconnect")
 }
 
+// resumeFromCursorLocked loads the cursor of Config.CursorStore, if any, to
+// continue subscribing where the previous instance stopped.
+// m must be locked.
+func (m *SubscriptionManager) resumeFromCursorLocked() {
+	store := m.pubnub.Config.CursorStore
+	if store == nil {
+		return
+	}
+
+	cursor, err := store.Load()
+	if err != nil {
+		m.pubnub.Config.Log.Println("cursor store: load failed", err)
+		return
+	}
+	if cursor == nil || cursor.Timetoken == 0 {
+		return
+	}
+
+	m.pubnub.Config.Log.Println("cursor store: resuming from", cursor.Timetoken, cursor.Region)
+	m.timetoken = cursor.Timetoken
+	m.storedRegion = cursor.Region
+}
+
+func (m *SubscriptionManager) saveCursor(cursor SubscribeCursor) {
+	store := m.pubnub.Config.CursorStore
+	if store == nil {
+		return
+	}
+
+	if err := store.Save(cursor); err != nil {
+		m.pubnub.Config.Log.Println("cursor store: save failed", err)
+	}
+}
+
+// releaseSubscription drops the references of a Subscription and unsubscribes
+// from the channels and channel groups no other Subscription holds.
+func (m *SubscriptionManager) releaseSubscription(subscription *Subscription) {
+	m.Lock()
+	channels := releaseRefs(m.channelRefs, subscription.channels, subscription.withPresence)
+	groups := releaseRefs(m.groupRefs, subscription.channelGroups, subscription.withPresence)
+	m.Unlock()
+
+	if len(channels) == 0 && len(groups) == 0 {
+		m.pubnub.Config.Log.Println("releaseSubscription: channels are still in use")
+		return
+	}
+
+	m.adaptUnsubscribe(&UnsubscribeOperation{
+		Channels:      channels,
+		ChannelGroups: groups,
+	})
+}
+
+func retainRefs(refs map[string]int, names []string, presence bool) {
+	for _, name := range names {
+		refs[name]++
+		if presence && !strings.HasSuffix(name, "-pnpres") {
+			refs[name+"-pnpres"]++
+		}
+	}
+}
+
+// releaseRefs returns the names which aren't referenced anymore.
+func releaseRefs(refs map[string]int, names []string, presence bool) []string {
+	var released []string
+	release := func(name string) {
+		if refs[name] <= 0 {
+			// already unsubscribed with Unsubscribe()
+			return
+		}
+		refs[name]--
+		if refs[name] == 0 {
+			delete(refs, name)
+			released = append(released, name)
+		}
+	}
+	for _, name := range names {
+		release(name)
+		if presence && !strings.HasSuffix(name, "-pnpres") {
+			release(name + "-pnpres")
+		}
+	}
+	return released
+}
+
+// clearRefs drops the references of the names unsubscribed explicitly.
+func clearRefs(refs map[string]int, names []string) {
+	for _, name := range names {
+		delete(refs, name)
+	}
+}
+
 func (m *SubscriptionManager) startSubscribeLoop() {
 	m.pubnub.Config.Log.Println("startSubscribeLoop")
+	generation := m.stateMachine.startLoop("subscribe loop started")
 	go subscribeMessageWorker(m)
 
 	go m.reconnectionManager.startPolling()
@@ -299,6 +447,7 @@ func (m *SubscriptionManager) startSubscribeLoop() {
 
 		if len(combinedChannels) == 0 && len(combinedGroups) == 0 {
 			m.pubnub.Config.Log.Println("no channels left to subscribe")
+			m.stateMachine.transition(generation, PNSubscribeStateStopped, "no channels left to subscribe", nil)
 			m.listenerManager.announceStatus(&PNStatus{
 				Category: PNDisconnectedCategory,
 			})
@@ -308,8 +457,11 @@ func (m *SubscriptionManager) startSubscribeLoop() {
 			break
 		}
 
+		m.checkStateUUID()
+
 		m.Lock()
 		tt := m.timetoken
+		region := m.region
 		ctx := m.ctx
 		m.Unlock()
 
@@ -318,11 +470,16 @@ func (m *SubscriptionManager) startSubscribeLoop() {
 			Channels:         combinedChannels,
 			ChannelGroups:    combinedGroups,
 			Timetoken:        tt,
-			Heartbeat:        m.pubnub.Config.PresenceTimeout,
 			FilterExpression: m.pubnub.Config.FilterExpression,
 			ctx:              ctx,
 			QueryParam:       m.queryParam,
 		}
+		if m.stateManager.hasHeartbeatItems() {
+			opts.Heartbeat = m.pubnub.Config.PresenceTimeout
+		}
+		if tt != 0 && region != 0 {
+			opts.Region = strconv.Itoa(int(region))
+		}
 
 		if s := m.stateManager.createStatePayload(); len(s) > 0 {
 			opts.State = s
@@ -335,78 +492,32 @@ func (m *SubscriptionManager) startSubscribeLoop() {
 		if err != nil {
 			m.pubnub.Config.Log.Println(err.Error())
 
-			if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "request canceled") {
-				m.listenerManager.announceStatus(&PNStatus{
-					Category: PNTimeoutCategory,
-				})
+			if m.handleSubscribeError(generation, ctx, err) {
 				m.pubnub.Config.Log.Println("continue")
 				continue
-			} else {
-
-				if strings.Contains(err.Error(), "context canceled") {
-					pnStatus := &PNStatus{
-						Category: PNCancelledCategory,
-					}
-					m.pubnub.Config.Log.Println("Status:", pnStatus)
-					m.listenerManager.announceStatus(pnStatus)
-					m.pubnub.Config.Log.Println("context canceled")
-					break
-				} else if strings.Contains(err.Error(), "Forbidden") ||
-					strings.Contains(err.Error(), "403") {
-					pnStatus := &PNStatus{
-						Category: PNAccessDeniedCategory,
-					}
-					m.pubnub.Config.Log.Println("Status:", pnStatus)
-					m.listenerManager.announceStatus(pnStatus)
-					m.unsubscribeAll()
-					break
-				} else if strings.Contains(err.Error(), "400") ||
-					strings.Contains(err.Error(), "Bad Request") {
-					pnStatus := &PNStatus{
-						Category: PNBadRequestCategory,
-					}
-					m.pubnub.Config.Log.Println("Status:", pnStatus)
-					m.listenerManager.announceStatus(pnStatus)
-					m.unsubscribeAll()
-					break
-				} else if strings.Contains(err.Error(), "530") || strings.Contains(err.Error(), "No Stub Matched") {
-					pnStatus := &PNStatus{
-						Category: PNNoStubMatchedCategory,
-					}
-					m.pubnub.Config.Log.Println("Status:", pnStatus)
-					m.listenerManager.announceStatus(pnStatus)
-					m.unsubscribeAll()
-					break
-				} else {
-					pnStatus := &PNStatus{
-						Category: PNUnknownCategory,
-					}
-					m.pubnub.Config.Log.Println("Status:", pnStatus)
-					m.listenerManager.announceStatus(pnStatus)
-
-					break
-				}
 			}
-
+			break
 		}
 
+		m.stateMachine.transition(generation, PNSubscribeStateReceiving, "subscribe response received", nil)
+		m.reconnectionManager.resetFailover()
+
 		m.Lock()
 		announced := m.subscriptionStateAnnounced
+		m.subscriptionStateAnnounced = true
+		m.Unlock()
 
 		if announced == false {
-
 			m.listenerManager.announceStatus(&PNStatus{
 				Category: PNConnectedCategory,
 			})
-			m.subscriptionStateAnnounced = true
 		}
-		m.Unlock()
 
 		var envelope subscribeEnvelope
 		err = json.Unmarshal(res, &envelope)
 		if err != nil {
 			pnStatus := &PNStatus{
-				Category:              PNBadRequestCategory,
+				Category:              PNMalformedResponseCategory,
 				ErrorData:             err,
 				Error:                 true,
 				Operation:             PNSubscribeOperation,
@@ -435,33 +546,150 @@ func (m *SubscriptionManager) startSubscribeLoop() {
 			}
 		}
 
+		var parseStatus *PNStatus
+		nextRegion := envelope.Metadata.Region
 		m.Lock()
 		if m.storedTimetoken != -1 {
 
 			m.timetoken = m.storedTimetoken
 			m.storedTimetoken = -1
+			if m.storedRegion != 0 {
+				nextRegion = m.storedRegion
+				m.storedRegion = 0
+			}
 		} else {
 			tt, err := strconv.ParseInt(envelope.Metadata.Timetoken, 10, 64)
 			if err != nil {
 
-				pnStatus := &PNStatus{
-					Category:              PNBadRequestCategory,
+				parseStatus = &PNStatus{
+					Category:              PNMalformedResponseCategory,
 					ErrorData:             err,
 					Error:                 true,
 					Operation:             PNSubscribeOperation,
 					AffectedChannels:      combinedChannels,
 					AffectedChannelGroups: combinedGroups,
 				}
-				m.pubnub.Config.Log.Println("ParseInt: err", err, pnStatus)
-				m.listenerManager.announceStatus(pnStatus)
+				m.pubnub.Config.Log.Println("ParseInt: err", err, parseStatus)
 			}
 
 			m.timetoken = tt
 		}
 
-		m.region = envelope.Metadata.Region
+		m.region = nextRegion
+		cursor := SubscribeCursor{Timetoken: m.timetoken, Region: m.region}
 		m.Unlock()
+
+		if parseStatus != nil {
+			// announced outside of the lock, announcing may block
+			m.listenerManager.announceStatus(parseStatus)
+		}
+
+		if m.pubnub.Config.CursorStore != nil && cursor.Timetoken != 0 {
+			m.messages <- subscribeMessage{cursor: &cursor}
+		}
+	}
+}
+
+// subscribeErrorAction describes how the subscribe loop reacts to a failed
+// request: the status to announce, the next state and whether to keep looping.
+type subscribeErrorAction struct {
+	category    StatusCategory
+	state       SubscribeState
+	reason      string
+	retry       bool
+	delay       time.Duration
+	unsubscribe bool
+}
+
+// subscribeServerErrorRetryDelay is the pause before resubscribing after a
+// retryable server error (429, 5xx).
+var subscribeServerErrorRetryDelay = time.Duration(reconnectionMinExponentialBackoff) * time.Second
+
+func (m *SubscriptionManager) classifySubscribeError(err error) subscribeErrorAction {
+	category := categoryForError(err)
+	action := subscribeErrorAction{
+		category: category,
+	}
+
+	switch category {
+	case PNTimeoutCategory:
+		action.state = PNSubscribeStateReconnecting
+		action.reason = "subscribe request timed out"
+		action.retry = true
+	case PNCancelledCategory:
+		action.state = PNSubscribeStateStopped
+		action.reason = "subscribe loop cancelled"
+	case PNAccessDeniedCategory, PNBadRequestCategory, PNNoStubMatchedCategory:
+		action.state = PNSubscribeStateFailed
+		action.reason = category.String()
+		action.unsubscribe = true
+	case PNTooManyRequestsCategory, PNServerErrorCategory:
+		action.state = PNSubscribeStateReconnecting
+		action.reason = category.String()
+		action.retry = true
+		action.delay = subscribeServerErrorRetryDelay
+	default:
+		action.state = PNSubscribeStateFailed
+		action.reason = "subscribe request failed"
+		var connErr *pnerr.ConnectionError
+		if errors.As(err, &connErr) && pnerr.IsRetryable(err) && m.pubnub.originManager.canFailover() {
+			// executeRequest rotated the origin, resubscribe on the next one
+			if delay, ok := m.reconnectionManager.nextFailoverDelay(); ok {
+				action.state = PNSubscribeStateReconnecting
+				action.reason = "subscribe request failed, failing over to the next origin"
+				action.retry = true
+				action.delay = delay
+			} else if m.pubnub.Config.PNReconnectionPolicy != PNNonePolicy {
+				action.reason = "subscribe request failed, reconnection attempts exhausted"
+			}
+		} else if pnerr.IsRetryable(err) && m.pubnub.Config.PNReconnectionPolicy != PNNonePolicy {
+			// the reconnection manager polls the network and restarts the loop
+			action.state = PNSubscribeStateReconnecting
+			action.reason = "subscribe request failed, waiting for the network"
+		}
+		if category != PNUnknownCategory {
+			action.reason = fmt.Sprintf("%s: %s", action.reason, category)
+		}
 	}
+	return action
+}
+
+// handleSubscribeError moves the state machine according to the error,
+// announces the status and returns true if the loop should keep going.
+func (m *SubscriptionManager) handleSubscribeError(generation int64, ctx Context, err error) bool {
+	action := m.classifySubscribeError(err)
+
+	m.stateMachine.transition(generation, action.state, action.reason, err)
+
+	pnStatus := &PNStatus{
+		Category:  action.category,
+		Operation: PNSubscribeOperation,
+		ErrorData: err,
+		Error:     action.category != PNTimeoutCategory && action.category != PNCancelledCategory,
+	}
+	var serverErr *pnerr.ServerError
+	if errors.As(err, &serverErr) {
+		pnStatus.StatusCode = serverErr.StatusCode
+	}
+	m.pubnub.Config.Log.Println("Status:", pnStatus)
+	m.listenerManager.announceStatus(pnStatus)
+
+	if action.unsubscribe {
+		m.unsubscribeAll()
+	}
+
+	if action.retry && action.delay > 0 {
+		var done <-chan struct{}
+		if ctx != nil {
+			done = ctx.Done()
+		}
+		select {
+		case <-time.After(action.delay):
+		case <-done:
+		}
+	}
+
+	return action.retry
 }
 
 type subscribeEnvelope struct {
@@ -484,7 +712,33 @@ type subscribeMessage struct {
 	MessageType       PNMessageType `json:"e"`
 	SequenceNumber    int           `json:"s"`
 
-	PublishMetaData publishMetadata `json:"p"`
+	PublishMetaData     publishMetadata      `json:"p"`
+	OriginationMetaData *originationMetadata `json:"o"`
+
+	// cursor is set on the marker queued after the messages of a subscribe
+	// response, the cursor is saved once they were processed.
+	cursor     *SubscribeCursor
+	rawPayload json.RawMessage
+}
+
+// UnmarshalJSON keeps the raw payload along the decoded one, the messages and
+// signals are decoded again with Config.Serializer.
+func (m *subscribeMessage) UnmarshalJSON(data []byte) error {
+	type message subscribeMessage
+	var v struct {
+		message
+		RawPayload json.RawMessage `json:"d"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*m = subscribeMessage(v.message)
+	m.rawPayload = v.RawPayload
+	if len(m.rawPayload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(m.rawPayload, &m.Payload)
 }
 
 type presenceEnvelope struct {
@@ -501,8 +755,22 @@ type publishMetadata struct {
 }
 
 type originationMetadata struct {
-	Timetoken int64 `json:"t"`
-	Region    int   `json:"r"`
+	Timetoken string `json:"t"`
+	Region    int    `json:"r"`
+}
+
+func (payload subscribeMessage) envelope() PNEnvelope {
+	envelope := PNEnvelope{
+		Shard:          payload.Shard,
+		Flags:          payload.Flags,
+		SubscribeKey:   payload.SubscribeKey,
+		SequenceNumber: payload.SequenceNumber,
+		PublishRegion:  payload.PublishMetaData.Region,
+	}
+	if payload.OriginationMetaData != nil {
+		envelope.OriginationRegion = payload.OriginationMetaData.Region
+	}
+	return envelope
 }
 
 func subscribeMessageWorker(m *SubscriptionManager) {
@@ -542,6 +810,10 @@ SubscribeMessageWorkerLabel:
 			break SubscribeMessageWorkerLabel
 		case message := <-m.messages:
 			m.pubnub.Config.Log.Println("subscribeMessageWorker messages")
+			if message.cursor != nil {
+				m.saveCursor(*message.cursor)
+				continue
+			}
 			processSubscribePayload(m, message)
 		}
 	}
@@ -623,8 +895,30 @@ func processPresencePayload(m *SubscriptionManager, payload subscribeMessage, ch
 		UUID:              uuid,
 		Timestamp:         timestamp,
 		HereNowRefresh:    hereNowRefresh,
+		Join:              parsePresenceUUIDs(presencePayload["join"]),
+		Leave:             parsePresenceUUIDs(presencePayload["leave"]),
+		Timeout:           parsePresenceUUIDs(presencePayload["timeout"]),
 	}
 	m.listenerManager.announcePresence(pnPresenceResult)
+
+	if m.isOwnTimeout(pnPresenceResult) {
+		go m.restoreState("timed out")
+	}
+}
+
+// parsePresenceUUIDs parses the UUID lists of the interval mode presence events.
+func parsePresenceUUIDs(value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	uuids := make([]string, 0, len(list))
+	for _, item := range list {
+		if uuid, ok := item.(string); ok {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids
 }
 
 func processNonPresencePayload(m *SubscriptionManager, payload subscribeMessage, channel, subscriptionMatch string, publishMeta publishMetadata) {
@@ -638,9 +932,30 @@ func processNonPresencePayload(m *SubscriptionManager, payload subscribeMessage,
 	}
 	var messagePayload interface{}
 
+	switch payload.MessageType {
+	case PNMessageTypeSignal, PNMessageTypeFile, 0: // 0 is a regular message
+		if m.isDuplicate(channel, publishMeta.PublishTimetoken, payload.IssuingClientID) {
+			m.pubnub.Config.Log.Println("skipping duplicate message,", channel, publishMeta.PublishTimetoken)
+			return
+		}
+		m.checkSequence(payload.IssuingClientID, channel, payload.SequenceNumber)
+	}
+
 	switch payload.MessageType {
 	case PNMessageTypeSignal:
-		pnMessageResult := createPNMessageResult(payload.Payload, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken)
+		signalPayload, err := decodePayload(payload.rawPayload, payload.Payload, m.pubnub.Config)
+		if err != nil {
+			pnStatus := &PNStatus{
+				Category:         PNBadRequestCategory,
+				ErrorData:        err,
+				Error:            true,
+				Operation:        PNSubscribeOperation,
+				AffectedChannels: []string{channel},
+			}
+			m.pubnub.Config.Log.Println("Unmarshal: err", err, pnStatus)
+			m.listenerManager.announceStatus(pnStatus)
+		}
+		pnMessageResult := createPNMessageResult(signalPayload, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken, payload.envelope())
 		m.pubnub.Config.Log.Println("announceSignal,", pnMessageResult)
 		m.listenerManager.announceSignal(pnMessageResult)
 	case PNMessageTypeObjects:
@@ -677,12 +992,12 @@ func processNonPresencePayload(m *SubscriptionManager, payload subscribeMessage,
 
 		}
 
-		pnFilesEvent := createPNFilesEvent(messagePayload, m, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken)
+		pnFilesEvent := createPNFilesEvent(messagePayload, m, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken, payload.envelope())
 		m.pubnub.Config.Log.Println("PNMessageTypeFile:", PNMessageTypeFile)
 		m.listenerManager.announceFile(pnFilesEvent)
 	default:
 		var err error
-		messagePayload, err = parseCipherInterface(payload.Payload, m.pubnub.Config)
+		messagePayload, err = decodeMessage(payload.rawPayload, payload.Payload, m.pubnub.Config)
 		if err != nil {
 			pnStatus := &PNStatus{
 				Category:         PNBadRequestCategory,
@@ -695,13 +1010,56 @@ func processNonPresencePayload(m *SubscriptionManager, payload subscribeMessage,
 			m.listenerManager.announceStatus(pnStatus)
 
 		}
-		pnMessageResult := createPNMessageResult(messagePayload, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken)
+		if !m.trackDelivery(channel, timetoken) {
+			m.pubnub.Config.Log.Println("skipping message delivered by catch-up,", channel, timetoken)
+			break
+		}
+		pnMessageResult := createPNMessageResult(messagePayload, actualCh, subscribedCh, channel, subscriptionMatch, payload.IssuingClientID, payload.UserMetadata, timetoken, payload.envelope())
 		m.pubnub.Config.Log.Println("announceMessage,", pnMessageResult)
 		m.listenerManager.announceMessage(pnMessageResult)
 	}
 	m.pubnub.Config.Log.Println("after announceMessage")
 }
 
+// isDuplicate reports whether a message with the same publish timetoken,
+// channel and publisher was already announced, see Config.DedupeCacheSize.
+func (m *SubscriptionManager) isDuplicate(channel, timetoken, publisher string) bool {
+	m.pubnub.Config.RLock()
+	size := m.pubnub.Config.DedupeCacheSize
+	ttl := time.Duration(m.pubnub.Config.DedupeCacheTTL) * time.Second
+	m.pubnub.Config.RUnlock()
+
+	if size <= 0 || timetoken == "" {
+		return false
+	}
+
+	return m.dedupe.isDuplicate(timetoken+"/"+channel+"/"+publisher, size, ttl, time.Now())
+}
+
+// checkSequence announces a PNSequenceGapCategory status when messages of
+// publisher were lost, see Config.DetectSequenceGaps.
+func (m *SubscriptionManager) checkSequence(publisher, channel string, sequence int) {
+	if !m.pubnub.Config.DetectSequenceGaps {
+		return
+	}
+	if gap := m.sequences.track(publisher, channel, sequence); gap != nil {
+		m.pubnub.Config.Log.Println("sequence gap,", gap)
+		m.listenerManager.announceStatus(&PNStatus{
+			Category:         PNSequenceGapCategory,
+			Operation:        PNSubscribeOperation,
+			Error:            true,
+			ErrorData:        gap,
+			AffectedChannels: []string{channel},
+		})
+	}
+}
+
+// SuppressedDuplicates returns the number of duplicate messages suppressed by
+// the de-duplication cache.
+func (m *SubscriptionManager) SuppressedDuplicates() int64 {
+	return m.dedupe.suppressedCount()
+}
+
 func processSubscribePayload(m *SubscriptionManager, payload subscribeMessage) {
 	channel := payload.Channel
 	subscriptionMatch := payload.SubscriptionMatch
@@ -718,7 +1076,7 @@ func processSubscribePayload(m *SubscriptionManager, payload subscribeMessage) {
 	}
 }
 
-func createPNFilesEvent(filePayload interface{}, m *SubscriptionManager, actualCh, subscribedCh, channel, subscriptionMatch, issuingClientID string, userMetadata interface{}, timetoken int64) *PNFilesEvent {
+func createPNFilesEvent(filePayload interface{}, m *SubscriptionManager, actualCh, subscribedCh, channel, subscriptionMatch, issuingClientID string, userMetadata interface{}, timetoken int64, envelope PNEnvelope) *PNFilesEvent {
 	var filesPayload map[string]interface{}
 	var ok bool
 	if filesPayload, ok = filePayload.(map[string]interface{}); !ok {
@@ -749,6 +1107,7 @@ func createPNFilesEvent(filePayload interface{}, m *SubscriptionManager, actualC
 		Timetoken:         timetoken,
 		Publisher:         issuingClientID,
 		UserMetadata:      userMetadata,
+		Envelope:          envelope,
 	}
 	return pnFilesEvent
 }
@@ -935,7 +1294,7 @@ func createPNObjectsResult(objPayload interface{}, m *SubscriptionManager, actua
 	return pnUUIDEvent, pnChannelEvent, pnMembershipEvent, eventType
 }
 
-func createPNMessageResult(messagePayload interface{}, actualCh, subscribedCh, channel, subscriptionMatch, issuingClientID string, userMetadata interface{}, timetoken int64) *PNMessage {
+func createPNMessageResult(messagePayload interface{}, actualCh, subscribedCh, channel, subscriptionMatch, issuingClientID string, userMetadata interface{}, timetoken int64, envelope PNEnvelope) *PNMessage {
 
 	pnMessageResult := &PNMessage{
 		Message:           messagePayload,
@@ -946,22 +1305,23 @@ func createPNMessageResult(messagePayload interface{}, actualCh, subscribedCh, c
 		Timetoken:         timetoken,
 		Publisher:         issuingClientID,
 		UserMetadata:      userMetadata,
+		Envelope:          envelope,
 	}
 
 	return pnMessageResult
 
 }
 
-// parseCipherInterface handles the decryption in case a cipher key is used
-// in case of error it returns data as is.
+// parseCipherInterface handles the decryption in case a crypto module or a
+// cipher key is used in case of error it returns data as is.
 //
 // parameters
 // data: the data to decrypt as interface.
-// cipherKey: cipher key to use to decrypt.
+// pnConf: the config of the crypto module.
 //
 // returns the decrypted data as interface and error.
 func parseCipherInterface(data interface{}, pnConf *Config) (interface{}, error) {
-	if pnConf.CipherKey != "" {
+	if module := pnConf.cryptoModule(); module != nil {
 		pnConf.Log.Println("reflect.TypeOf(data).Kind()", reflect.TypeOf(data).Kind(), data)
 		switch v := data.(type) {
 		case map[string]interface{}:
@@ -971,13 +1331,13 @@ func parseCipherInterface(data interface{}, pnConf *Config) (interface{}, error)
 				msg, ok := v["pn_other"].(string)
 				if ok {
 					pnConf.Log.Println("v[pn_other]", v["pn_other"], v, msg)
-					decrypted, errDecryption := utils.DecryptString(pnConf.CipherKey, msg, pnConf.UseRandomInitializationVector)
+					decrypted, errDecryption := decryptString(module, msg)
 					if errDecryption != nil {
 						pnConf.Log.Println(errDecryption, msg)
 						return v, errDecryption
 					} else {
 						var intf interface{}
-						err := json.Unmarshal([]byte(decrypted.(string)), &intf)
+						err := pnConf.serializer().Unmarshal([]byte(decrypted), &intf)
 						if err != nil {
 							pnConf.Log.Println("Unmarshal: err", err)
 							return intf, err
@@ -994,7 +1354,7 @@ func parseCipherInterface(data interface{}, pnConf *Config) (interface{}, error)
 			return v, nil
 		case string:
 			var intf interface{}
-			decrypted, errDecryption := utils.DecryptString(pnConf.CipherKey, data.(string), pnConf.UseRandomInitializationVector)
+			decrypted, errDecryption := decryptString(module, v)
 			if errDecryption != nil {
 				pnConf.Log.Println(errDecryption, intf)
 				intf = data
@@ -1002,7 +1362,7 @@ func parseCipherInterface(data interface{}, pnConf *Config) (interface{}, error)
 			}
 			pnConf.Log.Println("reflect.TypeOf(intf).Kind()", reflect.TypeOf(decrypted).Kind(), decrypted)
 
-			err := json.Unmarshal([]byte(decrypted.(string)), &intf)
+			err := pnConf.serializer().Unmarshal([]byte(decrypted), &intf)
 			if err != nil {
 				pnConf.Log.Println("Unmarshal: err", err)
 				return intf, err
@@ -1029,6 +1389,16 @@ func (m *SubscriptionManager) RemoveListener(listener *Listener) {
 	m.listenerManager.removeListener(listener)
 }
 
+// AddEventListener adds a new callback based listener.
+func (m *SubscriptionManager) AddEventListener(listener *EventListener) {
+	m.listenerManager.addEventListener(listener)
+}
+
+// RemoveEventListener removes the callback based listener.
+func (m *SubscriptionManager) RemoveEventListener(listener *EventListener) {
+	m.listenerManager.removeEventListener(listener)
+}
+
 // RemoveAllListeners removes all the listeners.
 func (m *SubscriptionManager) RemoveAllListeners() {
 	m.listenerManager.removeAllListeners()
@@ -1040,6 +1410,22 @@ func (m *SubscriptionManager) GetListeners() map[*Listener]bool {
 	return listn
 }
 
+// GetState returns the current state of the subscribe loop.
+func (m *SubscriptionManager) GetState() SubscribeState {
+	return m.stateMachine.currentState()
+}
+
+// GetLastStateTransition returns the latest transition of the subscribe loop state.
+func (m *SubscriptionManager) GetLastStateTransition() SubscribeStateTransition {
+	return m.stateMachine.lastTransition()
+}
+
+// AddStateHandler adds a handler which is called on every transition of the
+// subscribe loop state. Handlers are called synchronously and must not block.
+func (m *SubscriptionManager) AddStateHandler(handler func(SubscribeStateTransition)) {
+	m.stateMachine.addHandler(handler)
+}
+
 func (m *SubscriptionManager) reconnect() {
 	m.pubnub.Config.Log.Println("reconnect")
 	m.reconnectionManager.stopHeartbeatTimer()
@@ -1051,6 +1437,7 @@ func (m *SubscriptionManager) reconnect() {
 
 	if len(combinedChannels) == 0 && len(combinedGroups) == 0 {
 		m.pubnub.Config.Log.Println("All channels or channel groups unsubscribed.")
+		m.stateMachine.transition(0, PNSubscribeStateStopped, "all channels and channel groups unsubscribed", nil)
 	} else {
 		go m.startSubscribeLoop()
 		go m.pubnub.heartbeatManager.startHeartbeatTimer(false)
@@ -1075,11 +1462,13 @@ func (m *SubscriptionManager) Disconnect() {
 func (m *SubscriptionManager) stopSubscribeLoop() {
 	m.log("loop stop")
 
+	m.Lock()
 	if m.ctx != nil && m.subscribeCancel != nil {
 		m.subscribeCancel()
 		m.ctx = nil
 		m.subscribeCancel = nil
 	}
+	m.Unlock()
 
 }
 
@@ -1,10 +1,13 @@
 package pubnub
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -569,3 +572,91 @@ func TestProcessSubscribePayloadCipherErr(t *testing.T) {
 	<-done
 	//pn.Destroy()
 }
+
+func TestProcessSubscribePayloadEnvelope(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	var envelope subscribeEnvelope
+	err := json.Unmarshal([]byte(`{"t":{"t":"15","r":4},"m":[{"a":"3","f":514,"i":"publisher","s":12,"p":{"t":"14","r":2},"o":{"t":"13","r":7},"k":"demo","c":"channel","d":"hi"}]}`), &envelope)
+	assert.Nil(err)
+
+	processSubscribePayload(pn.subscriptionManager, envelope.Messages[0])
+
+	select {
+	case message := <-listener.Message:
+		assert.Equal("hi", message.Message)
+		assert.Equal(PNEnvelope{
+			Shard:             "3",
+			Flags:             514,
+			SubscribeKey:      "demo",
+			SequenceNumber:    12,
+			OriginationRegion: 7,
+			PublishRegion:     2,
+		}, message.Envelope)
+	case <-time.After(2 * time.Second):
+		assert.Fail("message not delivered")
+	}
+}
+
+func TestProcessSubscribePayloadSequenceGap(t *testing.T) {
+	assert := assert.New(t)
+	config := NewDemoConfig()
+	config.DetectSequenceGaps = true
+	pn := NewPubNub(config)
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	for _, sequence := range []int{1, 2, 5} {
+		processSubscribePayload(pn.subscriptionManager, subscribeMessage{
+			Channel:         "channel",
+			IssuingClientID: "publisher",
+			SequenceNumber:  sequence,
+			Payload:         "hi",
+			PublishMetaData: publishMetadata{PublishTimetoken: strconv.Itoa(sequence)},
+		})
+	}
+
+	select {
+	case status := <-listener.Status:
+		assert.Equal(PNSequenceGapCategory, status.Category)
+		assert.Equal([]string{"channel"}, status.AffectedChannels)
+		gap, ok := status.ErrorData.(*PNSequenceGapError)
+		if assert.True(ok) {
+			assert.Equal("publisher", gap.Publisher)
+			assert.Equal(3, gap.Expected)
+			assert.Equal(5, gap.Received)
+		}
+	case <-time.After(2 * time.Second):
+		assert.Fail("sequence gap not announced")
+	}
+	for i := 0; i < 3; i++ {
+		<-listener.Message
+	}
+}
+
+func TestProcessSubscribePayloadSequenceGapDisabled(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+	listener := NewListener()
+	pn.AddListener(listener)
+
+	for _, sequence := range []int{1, 5} {
+		processSubscribePayload(pn.subscriptionManager, subscribeMessage{
+			Channel:         "channel",
+			IssuingClientID: "publisher",
+			SequenceNumber:  sequence,
+			Payload:         "hi",
+			PublishMetaData: publishMetadata{PublishTimetoken: strconv.Itoa(sequence)},
+		})
+		<-listener.Message
+	}
+
+	select {
+	case status := <-listener.Status:
+		assert.Fail("unexpected status", "%v", status)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
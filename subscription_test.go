@@ -0,0 +1,149 @@
+package pubnub
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pubnub/go/v7/tests/stubs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStubbedPubNub() *PubNub {
+	interceptor := stubs.NewInterceptor()
+	config := NewDemoConfig()
+	config.SuppressLeaveEvents = true
+	pn := NewPubNub(config)
+	pn.SetSubscribeClient(interceptor.GetClient())
+	pn.SetClient(interceptor.GetClient())
+	return pn
+}
+
+func TestSubscriptionScopedListener(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	sub := newSubscription(pn, &SubscribeOperation{Channels: []string{"a"}, ChannelGroups: []string{"cg"}})
+	listener := NewListener()
+	sub.AddListener(listener)
+
+	lm := pn.subscriptionManager.listenerManager
+	lm.announceMessage(&PNMessage{Message: "other", Channel: "b"})
+	lm.announceMessage(&PNMessage{Message: "group", Channel: "c", Subscription: "cg"})
+	lm.announceMessage(&PNMessage{Message: "mine", Channel: "a"})
+	lm.announcePresence(&PNPresence{Event: "join", Channel: "a"})
+
+	for _, expected := range []string{"group", "mine"} {
+		select {
+		case m := <-listener.Message:
+			assert.Equal(expected, m.Message)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message not delivered")
+			return
+		}
+	}
+
+	select {
+	case m := <-listener.Message:
+		assert.Fail("unexpected message", "%v", m.Message)
+	case p := <-listener.Presence:
+		assert.Fail("unexpected presence event without WithPresence", "%v", p.Event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscriptionScopedStatus(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	statuses := make(chan *PNStatus, 3)
+	sub := newSubscription(pn, &SubscribeOperation{Channels: []string{"a"}, PresenceEnabled: true})
+	sub.AddEventListener(&EventListener{
+		OnStatus: func(status *PNStatus) {
+			statuses <- status
+		},
+	})
+
+	lm := pn.subscriptionManager.listenerManager
+	lm.announceStatus(&PNStatus{Category: PNAcknowledgmentCategory, AffectedChannels: []string{"b"}})
+	lm.announceStatus(&PNStatus{Category: PNAccessDeniedCategory, AffectedChannels: []string{"a-pnpres"}})
+	lm.announceStatus(&PNStatus{Category: PNConnectedCategory})
+
+	for _, expected := range []StatusCategory{PNAccessDeniedCategory, PNConnectedCategory} {
+		select {
+		case s := <-statuses:
+			assert.Equal(expected, s.Category)
+		case <-time.After(2 * time.Second):
+			assert.Fail("status not delivered")
+			return
+		}
+	}
+}
+
+func TestSubscriptionSetListener(t *testing.T) {
+	assert := assert.New(t)
+	pn := NewPubNub(NewDemoConfig())
+
+	a := newSubscription(pn, &SubscribeOperation{Channels: []string{"a"}})
+	b := newSubscription(pn, &SubscribeOperation{Channels: []string{"b"}})
+	set := pn.NewSubscriptionSet(a)
+	set.Add(b)
+	set.Add(b)
+	assert.Equal(2, len(set.Subscriptions()))
+
+	listener := NewListener()
+	set.AddListener(listener)
+	global := NewListener()
+	pn.AddListener(global)
+
+	lm := pn.subscriptionManager.listenerManager
+	lm.announceMessage(&PNMessage{Message: "c", Channel: "c"})
+	lm.announceMessage(&PNMessage{Message: "b", Channel: "b"})
+
+	select {
+	case m := <-listener.Message:
+		assert.Equal("b", m.Message)
+	case <-time.After(2 * time.Second):
+		assert.Fail("message not delivered")
+	}
+	for _, expected := range []string{"c", "b"} {
+		select {
+		case m := <-global.Message:
+			assert.Equal(expected, m.Message)
+		case <-time.After(2 * time.Second):
+			assert.Fail("message not delivered to the global listener")
+			return
+		}
+	}
+
+	set.Remove(b)
+	assert.Equal([]*Subscription{a}, set.Subscriptions())
+}
+
+func TestSubscriptionUnsubscribeReferenceCounted(t *testing.T) {
+	assert := assert.New(t)
+	pn := newStubbedPubNub()
+	defer pn.Destroy()
+
+	first := pn.Subscribe().Channels([]string{"shared", "first"}).Execute()
+	second := pn.Subscribe().Channels([]string{"shared"}).WithPresence(true).Execute()
+
+	subscribed := func() []string {
+		chs := pn.GetSubscribedChannels()
+		sort.Strings(chs)
+		return chs
+	}
+	assert.Equal([]string{"first", "shared"}, subscribed())
+
+	first.Unsubscribe()
+	assert.Equal([]string{"shared"}, subscribed())
+	assert.Contains(pn.subscriptionManager.stateManager.prepareChannelList(true), "shared-pnpres")
+
+	// a second call doesn't release the references of the other subscription
+	first.Unsubscribe()
+	assert.Equal([]string{"shared"}, subscribed())
+
+	second.Unsubscribe()
+	assert.Equal([]string{}, subscribed())
+	assert.Equal([]string{}, pn.subscriptionManager.stateManager.prepareChannelList(true))
+}
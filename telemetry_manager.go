@@ -27,6 +27,8 @@ type TelemetryManager struct {
 	sync.RWMutex
 
 	operations map[string][]LatencyEntry
+	// rateLimitWaits are the times the publishes waited for the rate limiter.
+	rateLimitWaits map[string][]LatencyEntry
 
 	ctx Context
 
@@ -40,6 +42,7 @@ func newTelemetryManager(maxLatencyDataAge int, ctx Context) *TelemetryManager {
 	manager := &TelemetryManager{
 		maxLatencyDataAge: maxLatencyDataAge,
 		operations:        make(map[string][]LatencyEntry),
+		rateLimitWaits:    make(map[string][]LatencyEntry),
 		ctx:               ctx,
 	}
 
@@ -86,29 +89,65 @@ func (m *TelemetryManager) StoreLatency(latency float64, t OperationType) {
 	}
 }
 
+// OperationRateLimitWait returns a map of the average time in seconds the
+// requests waited for the publish rate limiter, by operation. It isn't sent
+// to the server.
+func (m *TelemetryManager) OperationRateLimitWait() map[string]string {
+	waits := make(map[string]string)
+
+	m.RLock()
+	for endpointName, entries := range m.rateLimitWaits {
+		waits[fmt.Sprintf("w_%s", endpointName)] = fmt.Sprint(averageLatencyFromData(entries))
+	}
+	m.RUnlock()
+
+	return waits
+}
+
+// StoreRateLimitWait stores the time in seconds a request waited for the
+// publish rate limiter.
+func (m *TelemetryManager) StoreRateLimitWait(wait float64, t OperationType) {
+	if wait <= 0 {
+		return
+	}
+	endpointName := telemetryEndpointNameForOperation(t)
+
+	m.Lock()
+	m.rateLimitWaits[endpointName] = append(m.rateLimitWaits[endpointName], LatencyEntry{
+		D: time.Now().Unix(),
+		L: wait,
+	})
+	m.Unlock()
+}
+
 // CleanUpTelemetryData cleans up telemetry data of all operations.
 func (m *TelemetryManager) CleanUpTelemetryData() {
 	currentTimestamp := time.Now().Unix()
 
 	m.Lock()
-	for endpoint, latencies := range m.operations {
+	m.cleanUpEntries(m.operations, currentTimestamp)
+	m.cleanUpEntries(m.rateLimitWaits, currentTimestamp)
+	m.ctx.Done()
+	m.Unlock()
+}
+
+func (m *TelemetryManager) cleanUpEntries(operations map[string][]LatencyEntry, currentTimestamp int64) {
+	for endpoint, latencies := range operations {
 		index := 0
 
 		for _, latency := range latencies {
 			if currentTimestamp-latency.D > int64(m.maxLatencyDataAge) {
-				m.operations[endpoint] = append(m.operations[endpoint][:index],
-					m.operations[endpoint][index+1:]...)
+				operations[endpoint] = append(operations[endpoint][:index],
+					operations[endpoint][index+1:]...)
 				continue
 			}
 			index++
 		}
 
-		if len(m.operations[endpoint]) == 0 {
-			delete(m.operations, endpoint)
+		if len(operations[endpoint]) == 0 {
+			delete(operations, endpoint)
 		}
 	}
-	m.ctx.Done()
-	m.Unlock()
 }
 
 func (m *TelemetryManager) startCleanUpTimer() {
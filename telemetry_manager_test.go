@@ -29,6 +29,8 @@ func TestCleanUp(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		manager.StoreLatency(float64(i), PNPublishOperation)
 	}
+	manager.StoreRateLimitWait(0.5, PNPublishOperation)
+	assert.Equal(map[string]string{"w_pub": "0.5"}, manager.OperationRateLimitWait())
 
 	// await for store timestamp expired
 	time.Sleep(2 * time.Second)
@@ -36,6 +38,7 @@ func TestCleanUp(t *testing.T) {
 	manager.CleanUpTelemetryData()
 
 	assert.Equal(0, len(manager.OperationLatency()))
+	assert.Equal(0, len(manager.OperationRateLimitWait()))
 }
 
 func TestValidQueries(t *testing.T) {
@@ -299,22 +299,18 @@ func TestFileEncryptionDecryption(t *testing.T) {
 	if err != nil {
 		panic(err)
 	}
-	utils.EncryptFile("enigma", []byte{133, 126, 158, 123, 43, 95, 96, 90, 215, 178, 17, 73, 166, 130, 79, 156}, out, file)
+	assert.Nil(utils.EncryptFile("enigma", []byte{133, 126, 158, 123, 43, 95, 96, 90, 215, 178, 17, 73, 166, 130, 79, 156}, out, file))
 	fileText, _ := ioutil.ReadFile(filepathOutput)
 
 	fileTextSample, _ := ioutil.ReadFile(filepathSampleOutput)
 	assert.Equal(string(fileTextSample), string(fileText))
 
 	outDec, _ := os.Open(filepathSampleOutput)
-	fi, _ := outDec.Stat()
-	contentLenEnc := fi.Size()
 	defer outDec.Close()
 
 	fileDec, _ := os.Create(filepathOutputDec)
 	defer fileDec.Close()
-	r, w := io.Pipe()
-	utils.DecryptFile("enigma", contentLenEnc, outDec, w)
-	io.Copy(fileDec, r)
+	assert.Nil(utils.DecryptFile("enigma", outDec, fileDec))
 	fileTextDec, _ := ioutil.ReadFile(filepathOutputDec)
 	fileTextIn, _ := ioutil.ReadFile(filepathInput)
 	assert.Equal(fileTextIn, fileTextDec)
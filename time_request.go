@@ -54,6 +54,13 @@ func (b *timeBuilder) QueryParam(queryParam map[string]string) *timeBuilder {
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *timeBuilder) RetryPolicy(policy RetryPolicy) *timeBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the Time request and fetches the time from the server.
 func (b *timeBuilder) Execute() (*TimeResponse, StatusResponse, error) {
 	rawJSON, status, err := executeRequest(b.opts)
@@ -69,6 +76,8 @@ type timeOpts struct {
 	QueryParam map[string]string
 	Transport  http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -138,6 +147,17 @@ func (o *timeOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *timeOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *timeOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // TimeResponse is the response when Time call is executed.
 type TimeResponse struct {
 	Timetoken int64
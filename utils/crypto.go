@@ -12,7 +12,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -29,14 +28,31 @@ var valIV = "0123456789012345"
 //
 // returns the base64 encoded encrypted string.
 func EncryptString(cipherKey string, message string, useRandomInitializationVector bool) string {
-	block, _ := aesCipher(cipherKey)
-
 	message = encodeNonASCIIChars(message)
-	value := []byte(message)
-	value = padWithPKCS7(value)
-	iv := make([]byte, aes.BlockSize)
+	cipherBytes, _ := EncryptBytes(cipherKey, []byte(message), useRandomInitializationVector)
+	return base64.StdEncoding.EncodeToString(cipherBytes)
+}
+
+// EncryptBytes encrypts data with AES-CBC using the cipherKey.
+// It accepts the following parameters:
+// cipherKey: cipher key to use to encrypt.
+// data: to encrypt.
+// useRandomInitializationVector: if true the IV is random and is prepended to the encrypted data
+//
+// returns the encrypted data,
+// error if any.
+func EncryptBytes(cipherKey string, data []byte, useRandomInitializationVector bool) ([]byte, error) {
+	block, err := aesCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	value := padWithPKCS7(append([]byte{}, data...))
+	var iv []byte
 	if useRandomInitializationVector {
-		iv = generateIV(aes.BlockSize)
+		if iv, err = generateIV(aes.BlockSize); err != nil {
+			return nil, err
+		}
 	} else {
 		iv = []byte(valIV)
 	}
@@ -45,9 +61,9 @@ func EncryptString(cipherKey string, message string, useRandomInitializationVect
 	cipherBytes := make([]byte, len(value))
 	blockmode.CryptBlocks(cipherBytes, value)
 	if useRandomInitializationVector {
-		return base64.StdEncoding.EncodeToString(append(iv, cipherBytes...))
+		return append(iv, cipherBytes...), nil
 	}
-	return base64.StdEncoding.EncodeToString(cipherBytes)
+	return cipherBytes, nil
 }
 
 type A struct {
@@ -73,38 +89,57 @@ func DecryptString(cipherKey string, message string, useRandomInitializationVect
 		return "**decrypt error***", errors.New("message is empty")
 	}
 
-	block, aesErr := aesCipher(cipherKey)
-	if aesErr != nil {
-		return "***decrypt error***", fmt.Errorf("decrypt error aes cipher: %s", aesErr)
-	}
-
 	value, decodeErr := base64.StdEncoding.DecodeString(message)
 	if decodeErr != nil {
 		return "***decrypt error***", fmt.Errorf("decrypt error on decode: %s", decodeErr)
 	}
-	iv := make([]byte, aes.BlockSize)
+
+	val, err := DecryptBytes(cipherKey, value, useRandomInitializationVector)
+	if err != nil {
+		return "***decrypt error***", err
+	}
+
+	return fmt.Sprintf("%s", string(val)), nil
+}
+
+// DecryptBytes decrypts data encrypted by EncryptBytes using the cipherKey.
+//
+// It accepts the following parameters:
+// cipherKey: cipher key to use to decrypt.
+// data: to decrypt.
+// useRandomInitializationVector: if true the IV is random and is prepended to the data.
+//
+// returns the decrypted data,
+// error if any.
+func DecryptBytes(cipherKey string, data []byte, useRandomInitializationVector bool) ([]byte, error) {
+	block, aesErr := aesCipher(cipherKey)
+	if aesErr != nil {
+		return nil, fmt.Errorf("decrypt error aes cipher: %s", aesErr)
+	}
+
+	var iv []byte
 	if useRandomInitializationVector {
-		iv = value[:16]
-		value = value[16:]
+		if len(data) < aes.BlockSize {
+			return nil, fmt.Errorf("decrypt error: invalid data len %d", len(data))
+		}
+		iv = data[:aes.BlockSize]
+		data = data[aes.BlockSize:]
 	} else {
 		iv = []byte(valIV)
 	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("decrypt error: invalid data len %d", len(data))
+	}
 
 	decrypter := cipher.NewCBCDecrypter(block, iv)
-	//to handle decryption errors
-	defer func() {
-		if r := recover(); r != nil {
-			retVal, err = "***decrypt error***", fmt.Errorf("decrypt error: %s", r)
-		}
-	}()
-	decrypted := make([]byte, len(value))
-	decrypter.CryptBlocks(decrypted, value)
+	decrypted := make([]byte, len(data))
+	decrypter.CryptBlocks(decrypted, data)
 	val, err := unpadPKCS7(decrypted)
 	if err != nil {
-		return "***decrypt error***", fmt.Errorf("decrypt error: %s", err)
+		return nil, fmt.Errorf("decrypt error: %s", err)
 	}
 
-	return fmt.Sprintf("%s", string(val)), nil
+	return val, nil
 }
 
 // aesCipher returns the cipher block
@@ -217,175 +252,203 @@ func unpadPKCS7(data []byte) ([]byte, error) {
 	return data[:len(data)-padlen], nil
 }
 
-func generateIV(blocksize int) []byte {
-	iv := make([]byte, aes.BlockSize)
+func generateIV(blocksize int) ([]byte, error) {
+	iv := make([]byte, blocksize)
 	if _, err := rand.Read(iv); err != nil {
-		panic(err)
+		return nil, err
 	}
-	return iv
+	return iv, nil
 }
 
-func EncryptFile(cipherKey string, iv []byte, filePart io.Writer, file *os.File) {
-	key := EncryptCipherKey(cipherKey)
-	block, err := aes.NewCipher(key)
+// fileChunkSize is the size of the chunks read by the file encryption and
+// decryption, it's a multiple of the block size.
+const fileChunkSize = 32 * 1024
+
+// EncryptFile encrypts file with AES-CBC using the cipherKey and writes it
+// to filePart.
+// It accepts the following parameters:
+// cipherKey: cipher key to use to encrypt.
+// iv: the IV written first, a random one is used when it's empty.
+// filePart: where the encrypted data is written.
+// file: the data to encrypt.
+//
+// returns the error if any.
+func EncryptFile(cipherKey string, iv []byte, filePart io.Writer, file io.Reader) error {
+	reader, err := NewEncryptReader(cipherKey, iv, file)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	if bytes.Equal(iv, []byte{}) {
-		iv = generateIV(aes.BlockSize)
+	_, err = io.Copy(filePart, reader)
+	return err
+}
+
+// DecryptFile decrypts the data encrypted by EncryptFile and writes it to w.
+// It accepts the following parameters:
+// cipherKey: cipher key to use to decrypt.
+// reader: the encrypted data.
+// w: where the decrypted data is written.
+//
+// returns the error if any, the data written before a padding error has to
+// be discarded.
+func DecryptFile(cipherKey string, reader io.Reader, w io.Writer) error {
+	decrypted, err := NewDecryptReader(cipherKey, reader)
+	if err != nil {
+		return err
 	}
-	_, e := filePart.Write(iv)
-	if e != nil {
-		panic(e)
+	_, err = io.Copy(w, decrypted)
+	return err
+}
+
+// NewEncryptReader returns a reader of src encrypted with AES-CBC using the
+// cipherKey, the data is read and encrypted as the reader is read. The IV is
+// read first, a random one is used when iv is empty, and the data is padded
+// as per the PKCS7 standard.
+func NewEncryptReader(cipherKey string, iv []byte, src io.Reader) (io.Reader, error) {
+	block, err := aesCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) == 0 {
+		if iv, err = generateIV(aes.BlockSize); err != nil {
+			return nil, err
+		}
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("encrypt error: invalid iv len %d", len(iv))
 	}
-	blockSize := 16
-	bufferSize := 16
-	p := make([]byte, bufferSize)
-
-	mode := cipher.NewCBCEncrypter(block, iv)
-	cryptoRan := false
-	fii, _ := file.Stat()
-	contentLenIn := fii.Size()
-	var contentRead int64
-
-	for {
-		n2, err2 := io.ReadFull(file, p)
-		contentRead += int64(n2)
-		if err2 != nil {
-			if err2 == io.EOF {
-				ciphertext := make([]byte, blockSize)
-				copy(ciphertext[:n2], p[:n2])
-				break
-			}
 
-			if err2 == io.ErrUnexpectedEOF {
-				if !cryptoRan {
-					text := make([]byte, blockSize)
-					ciphertext := make([]byte, blockSize)
-					copy(text[:n2], p[:n2])
-					pad := bytes.Repeat([]byte{byte(blockSize - n2)}, blockSize-n2)
-					copy(text[n2:], pad)
-					mode.CryptBlocks(ciphertext, text)
-					filePart.Write(ciphertext)
-				} else {
-					text := make([]byte, blockSize)
-					ciphertext := make([]byte, blockSize)
-					copy(text[:n2], p[:n2])
-					pad := bytes.Repeat([]byte{byte(blockSize - n2)}, blockSize-n2)
-					copy(text[n2:], pad)
-					mode.CryptBlocks(ciphertext, text)
-					filePart.Write(ciphertext)
-
-				}
+	return &cbcEncryptReader{
+		mode: cipher.NewCBCEncrypter(block, iv),
+		src:  src,
+		in:   make([]byte, fileChunkSize),
+		out:  append([]byte{}, iv...),
+	}, nil
+}
 
-			}
-			break
-		}
+// NewDecryptReader returns a reader of src, encrypted by NewEncryptReader,
+// decrypted with the cipherKey as the reader is read. Read returns an error
+// when src is truncated or its padding is invalid.
+func NewDecryptReader(cipherKey string, src io.Reader) (io.Reader, error) {
+	block, err := aesCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cbcDecryptReader{
+		block: block,
+		src:   src,
+		in:    make([]byte, fileChunkSize),
+	}, nil
+}
 
-		ciphertext := make([]byte, blockSize)
-		cryptoRan = true
-		if contentRead >= contentLenIn {
-			pad := bytes.Repeat([]byte{byte(blockSize - n2)}, blockSize-n2)
-			copy(p[n2:], pad)
+type cbcEncryptReader struct {
+	mode cipher.BlockMode
+	src  io.Reader
+	in   []byte
+	// out is the encrypted data not read yet.
+	out  []byte
+	done bool
+	err  error
+}
+
+func (r *cbcEncryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
 		}
+		r.err = r.next()
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
 
-		mode.CryptBlocks(ciphertext, p)
-		filePart.Write(ciphertext)
+func (r *cbcEncryptReader) next() error {
+	n, err := io.ReadFull(r.src, r.in)
+	data := r.in[:n]
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		// the last block is padded, a full block of padding is added when
+		// the data is a multiple of the block size
+		data = padWithPKCS7(data)
+		r.done = true
+	default:
+		return err
+	}
 
+	r.mode.CryptBlocks(data, data)
+	r.out = data
+	return nil
+}
+
+type cbcDecryptReader struct {
+	block cipher.Block
+	mode  cipher.BlockMode
+	src   io.Reader
+	in    []byte
+	// last is the last decrypted block, it's kept until the end of src is
+	// known to remove the padding.
+	last []byte
+	out  []byte
+	done bool
+	err  error
+}
+
+func (r *cbcDecryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
 	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
 }
 
-func DecryptFile(cipherKey string, contentLenEnc int64, reader io.Reader, w io.WriteCloser) {
-	key := EncryptCipherKey(cipherKey)
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		panic(err)
+func (r *cbcDecryptReader) next() error {
+	if r.mode == nil {
+		iv := make([]byte, aes.BlockSize)
+		if _, err := io.ReadFull(r.src, iv); err != nil {
+			return fmt.Errorf("decrypt error: reading iv: %s", err)
+		}
+		r.mode = cipher.NewCBCDecrypter(r.block, iv)
 	}
-	blockSize := 16
-	bufferSize := 16
-	p := make([]byte, bufferSize)
-	ivBuff := make([]byte, blockSize)
-	emptyByteVar := make([]byte, blockSize)
-
-	iv2 := make([]byte, blockSize)
-	count := 0
-
-	var mode cipher.BlockMode
-
-	cryptoRan := false
-	var contentDownloaded int64
-
-	go func() {
-	ExitReadLabel:
-		for {
-			n2, err2 := io.ReadFull(reader, p)
-			if err2 != nil {
-				if err2 == io.EOF {
-					ciphertext := make([]byte, blockSize)
-					copy(ciphertext, p[:n2])
-					ciphertext, _ = unpadPKCS7(ciphertext)
-					w.Write(ciphertext)
-					w.Close()
-					break ExitReadLabel
-				}
-
-				if err2 == io.ErrUnexpectedEOF {
-					if bytes.Equal(iv2, emptyByteVar) {
-						copy(iv2, ivBuff[0:blockSize])
-						mode = cipher.NewCBCDecrypter(block, iv2)
-					}
-					if !cryptoRan {
-						text := make([]byte, blockSize)
-						ciphertext := make([]byte, blockSize)
-						copy(text, p[:n2])
-						mode.CryptBlocks(ciphertext, text)
-						ciphertext, _ = unpadPKCS7(ciphertext)
-						w.Write(ciphertext)
-
-						w.Close()
-						break ExitReadLabel
-					} else {
-						ciphertext := make([]byte, blockSize)
-						copy(ciphertext, p[:n2])
-						ciphertext, _ = unpadPKCS7(ciphertext)
-						w.Write(ciphertext)
-						w.Close()
-						break ExitReadLabel
-					}
-
-				}
-				break ExitReadLabel
-			} else {
-				contentDownloaded += int64(n2)
-				if count < blockSize/bufferSize {
-					if err != nil {
-						panic(err)
-					}
-					copy(ivBuff[bufferSize*count:], p)
-				} else {
-
-					if bytes.Equal(iv2, emptyByteVar) {
-						copy(iv2, ivBuff[0:blockSize])
-						mode = cipher.NewCBCDecrypter(block, iv2)
-					}
-
-					ciphertext := make([]byte, blockSize)
-
-					text := make([]byte, blockSize)
-					copy(text, p[:n2])
-
-					mode.CryptBlocks(ciphertext, p)
-					cryptoRan = true
-					if contentDownloaded >= contentLenEnc {
-						ciphertext, _ = unpadPKCS7(ciphertext)
-						w.Write(ciphertext)
-					} else {
-						w.Write(ciphertext)
-					}
-				}
-			}
-			count++
 
+	n, err := io.ReadFull(r.src, r.in)
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	default:
+		return err
+	}
+	if n%aes.BlockSize != 0 {
+		return fmt.Errorf("decrypt error: invalid data len %d", n)
+	}
+
+	data := append(r.last, r.in[:n]...)
+	r.mode.CryptBlocks(data[len(r.last):], data[len(r.last):])
+	if last {
+		unpadded, err := unpadPKCS7(data)
+		if err != nil {
+			return fmt.Errorf("decrypt error: %s", err)
 		}
-	}()
+		r.out = unpadded
+		r.done = true
+		return nil
+	}
+
+	split := len(data) - aes.BlockSize
+	r.out = data[:split]
+	r.last = append([]byte{}, data[split:]...)
+	return nil
 }
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 
 	"github.com/stretchr/testify/assert"
@@ -463,6 +465,49 @@ func TestGermanEncryption(t *testing.T) {
 	assert.Equal("stpgsG1DZZxb44J7mFNSzg==", encrypted)
 }
 
+func TestEncryptFile(t *testing.T) {
+	assert := assert.New(t)
+	iv := []byte("0123456789abcdef")
+
+	for _, size := range []int{0, 1, 15, 16, 17, fileChunkSize - 1, fileChunkSize, fileChunkSize + 16, 3*fileChunkSize + 5} {
+		data := bytes.Repeat([]byte{'x'}, size)
+
+		var encrypted bytes.Buffer
+		assert.Nil(EncryptFile("enigma", iv, &encrypted, bytes.NewReader(data)), size)
+		assert.True(bytes.HasPrefix(encrypted.Bytes(), iv))
+		assert.Equal(0, encrypted.Len()%16)
+
+		decrypted, err := DecryptBytes("enigma", encrypted.Bytes(), true)
+		assert.Nil(err, size)
+		assert.Equal(data, decrypted, size)
+
+		var streamed bytes.Buffer
+		assert.Nil(DecryptFile("enigma", bytes.NewReader(encrypted.Bytes()), &streamed), size)
+		assert.Equal(data, streamed.Bytes(), size)
+	}
+}
+
+func TestDecryptFileErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, err := NewEncryptReader("enigma", nil, bytes.NewReader([]byte("some file content")))
+	assert.Nil(err)
+	encrypted, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+
+	// wrong key, the padding is invalid
+	assert.NotNil(DecryptFile("other", bytes.NewReader(encrypted), ioutil.Discard))
+	// truncated in a block
+	assert.NotNil(DecryptFile("enigma", bytes.NewReader(encrypted[:len(encrypted)-3]), ioutil.Discard))
+	// no data after the IV
+	assert.NotNil(DecryptFile("enigma", bytes.NewReader(encrypted[:16]), ioutil.Discard))
+	// shorter than the IV
+	assert.NotNil(DecryptFile("enigma", bytes.NewReader(encrypted[:5]), ioutil.Discard))
+
+	_, err = NewEncryptReader("enigma", []byte("short"), bytes.NewReader(nil))
+	assert.NotNil(err)
+}
+
 // EmptyStruct provided the empty struct to test the encryption.
 type emptyStruct struct {
 }
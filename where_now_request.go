@@ -57,6 +57,13 @@ func (b *whereNowBuilder) QueryParam(queryParam map[string]string) *whereNowBuil
 	return b
 }
 
+// RetryPolicy overrides Config.RetryPolicy for this request.
+func (b *whereNowBuilder) RetryPolicy(policy RetryPolicy) *whereNowBuilder {
+	b.opts.RetryPolicy = &policy
+
+	return b
+}
+
 // Execute runs the WhereNow request.
 func (b *whereNowBuilder) Execute() (*WhereNowResponse, StatusResponse, error) {
 	if len(b.opts.UUID) <= 0 {
@@ -78,6 +85,8 @@ type whereNowOpts struct {
 	QueryParam map[string]string
 	Transport  http.RoundTripper
 
+	RetryPolicy *RetryPolicy
+
 	ctx Context
 }
 
@@ -153,6 +162,17 @@ func (o *whereNowOpts) tokenManager() *TokenManager {
 	return o.pubnub.tokenManager
 }
 
+func (o *whereNowOpts) originManager() *OriginManager {
+	return o.pubnub.originManager
+}
+
+func (o *whereNowOpts) retryPolicy() RetryPolicy {
+	if o.RetryPolicy != nil {
+		return *o.RetryPolicy
+	}
+	return o.pubnub.Config.RetryPolicy
+}
+
 // WhereNowResponse is the response of the WhereNow request. Contains channels info.
 type WhereNowResponse struct {
 	Channels []string